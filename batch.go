@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// BatchResult is the response of the batch endpoint.
+type BatchResult struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Items    []BatchItemResult `json:"items"`
+}
+
+// BatchItemResult is the result of a stats object of a batch, in the batch order.
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	Client string `json:"client,omitempty"`
+	Ts     int64  `json:"ts,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer r.Body.Close()
+	log.Println(">> Handling stats batch from requester:: ", r.Header.Get("User-Agent"))
+	exp, ok := tenantExps.exporter(w, r)
+	if !ok {
+		return
+	}
+	var items []stats.BatchItem
+	if !readPayload(w, r, exp, func(body io.Reader) (err error) {
+		items, err = stats.DecodeBatch(body)
+		return err
+	}) {
+		return
+	}
+
+	errs := exp.UpdateClientBatch(items, clientInfo(r))
+	result := BatchResult{Items: make([]BatchItemResult, len(items))}
+	for i, item := range items {
+		res := BatchItemResult{Index: i, Status: "OK"}
+		if item.Stats != nil {
+			res.Client = strings.Join([]string{item.Stats.ClientID, item.Stats.Name, item.Stats.Type}, "/")
+			res.Ts = item.Stats.Ts
+		}
+		var limited *prom.LimitError
+		switch {
+		case errors.As(errs[i], &limited) && !limited.Rejected:
+			res.Status, res.Error = "PARTIAL", errs[i].Error()
+			result.Accepted++
+		case errs[i] != nil:
+			res.Status, res.Error = "ERROR", errs[i].Error()
+			result.Rejected++
+		default:
+			result.Accepted++
+		}
+		result.Items[i] = res
+	}
+	log.Printf("Batch Completed: %d accepted, %d rejected", result.Accepted, result.Rejected)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
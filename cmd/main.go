@@ -1,40 +1,116 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io"
 	"log"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
 	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
 	"os"
+	"regexp"
+	"sync"
 )
 
 func main() {
+	follow := flag.Bool("follow", false, "keep reading the files as they grow, following rotations, and print the metrics on every update")
+	prefix := flag.String("prefix", "", "regular expression matching the log prefix to strip before the stats JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: cmd [-follow] [-prefix regexp] [file|- ...]")
+		fmt.Fprintln(flag.CommandLine.Output(), "Without files, replays the stats.json recording.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	promExp := prom.NewPrometheusLibrdKafkaExporter()
 
-	jsonFile, err := os.Open("stats.json")
+	if flag.NArg() == 0 {
+		replay(promExp, "stats.json")
+		printMetrics(promExp)
+		return
+	}
+
+	parser := &ingest.LineParser{}
+	if *prefix != "" {
+		parser.Prefix = regexp.MustCompile(*prefix)
+	}
+	var mu sync.Mutex
+	handle := func(source string) func([]byte) {
+		return func(line []byte) {
+			s, err := parser.Parse(line)
+			if err != nil {
+				fmt.Println(source, err)
+				return
+			}
+			if s == nil {
+				return
+			}
+			if err := promExp.UpdateStats(s); err != nil {
+				fmt.Println("Error UpdateStats: ", source, err)
+				return
+			}
+			if *follow {
+				mu.Lock()
+				printMetrics(promExp)
+				mu.Unlock()
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, path := range flag.Args() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			switch {
+			case path == "-":
+				err = ingest.ReadLines(os.Stdin, ingest.DEFAULT_MAX_SIZE, handle("stdin"))
+			case *follow:
+				err = ingest.TailFile(context.Background(), path, true, ingest.DEFAULT_MAX_SIZE, handle(path))
+			default:
+				var f *os.File
+				if f, err = os.Open(path); err == nil {
+					err = ingest.ReadLines(f, ingest.DEFAULT_MAX_SIZE, handle(path))
+					f.Close()
+				}
+			}
+			if err != nil {
+				log.Println(path, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if !*follow {
+		printMetrics(promExp)
+	}
+}
+
+// replay reads a stats recording: a single stats object, a JSON array or newline-delimited JSON.
+func replay(promExp *prom.PrometheusLibrdKafkaExporter, path string) {
+	jsonFile, err := os.Open(path)
 	// if we os.Open returns an error then handle it
 	if err != nil {
-		fmt.Println(err)
+		log.Fatal(err)
 	}
-	fmt.Println("Successfully Opened users.json")
+	fmt.Println("Successfully Opened", path)
 	// defer the closing of our jsonFile so that we can parse it later on
 	defer jsonFile.Close()
 
-	byteValue, _ := io.ReadAll(jsonFile)
-	// Parse the JSON data
-	stats := make(map[string]interface{})
-
-	err = json.Unmarshal(byteValue, &stats) // Pass a pointer to Stats
+	items, err := stats.DecodeBatch(jsonFile)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 	// Build Prometheus stats
-	err = promExp.UpdateStats(stats)
-	if err != nil {
-		fmt.Println("Error UpdateStats: ", err)
+	for i, err := range promExp.UpdateBatch(items) {
+		if err != nil {
+			fmt.Println("Error UpdateStats: ", i, err)
+		}
 	}
+}
 
+func printMetrics(promExp *prom.PrometheusLibrdKafkaExporter) {
 	metrics, err := promExp.Registry.Gather() // Gather the metrics from the registry
 	if err != nil {
 		fmt.Println("Error gathering metrics: ", err)
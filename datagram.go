@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// serveDatagrams feeds the stats received on a "udp" address or a "unixgram" socket to the exporter.
+// Datagrams have no headers to name a tenant, so their stats go to the default exporter.
+func serveDatagrams(network, address string) {
+	conn, err := ingest.ListenDatagram(network, address)
+	if err != nil {
+		log.Fatalf("Listen %s %s: %v", network, address, err)
+	}
+	log.Printf("Listening on %s: %s", network, address)
+	if err := ingest.ServeDatagrams(conn, maxPayloadSize.Load, handleDatagram); err != nil {
+		log.Fatalf("Serve %s %s: %v", network, address, err)
+	}
+}
+
+func handleDatagram(payload []byte, addr net.Addr) {
+	body, err := ingest.NewBody(bytes.NewReader(payload), ingest.Sniff(payload), maxPayloadSize.Load())
+	if err != nil {
+		log.Printf("Datagram from %v: %v", addr, err)
+		return
+	}
+	defer body.Close()
+	s, err := stats.Decode(body)
+	promExp.CountIngest(body.Encoding, body.WireBytes(), body.DecodedBytes())
+	if err != nil {
+		log.Printf("Datagram from %v: %v", addr, err)
+		return
+	}
+	info := prom.ClientInfo{}
+	if addr != nil {
+		info.RemoteAddr = addr.String()
+		if host, _, err := net.SplitHostPort(info.RemoteAddr); err == nil {
+			info.RemoteAddr = host
+		}
+	}
+	if err := promExp.UpdateClientStats(s, info); err != nil {
+		log.Printf("Datagram from %v: %v", addr, err)
+	}
+}
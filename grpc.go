@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/rpc"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serveGRPC serves the gRPC StatsService on address.
+func serveGRPC(address string) {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("Listen grpc %s: %v", address, err)
+	}
+	log.Printf("Listening on grpc: %s", address)
+	if err := rpc.NewServer(grpcExporter, func() int { return int(maxPayloadSize.Load()) }).Serve(lis); err != nil {
+		log.Fatalf("Serve grpc %s: %v", address, err)
+	}
+}
+
+// grpcExporter returns the exporter of the tenant named by the metadata of a call, the tenant
+// header and authorization metadata playing the role of the HTTP headers.
+func grpcExporter(ctx context.Context) (*prom.PrometheusLibrdKafkaExporter, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tenant, err := tenantExps.resolve("", func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	})
+	if err == nil {
+		var exp *prom.PrometheusLibrdKafkaExporter
+		if exp, err = tenantExps.exporterOf(tenant); err == nil {
+			return exp, nil
+		}
+	}
+	code := codes.Internal
+	var tenantErr *tenantError
+	if errors.As(err, &tenantErr) {
+		switch tenantErr.status {
+		case http.StatusBadRequest:
+			code = codes.InvalidArgument
+		case http.StatusUnauthorized:
+			code = codes.Unauthenticated
+		case http.StatusForbidden:
+			code = codes.PermissionDenied
+		case http.StatusTooManyRequests:
+			code = codes.ResourceExhausted
+		}
+	}
+	return nil, status.Error(code, err.Error())
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/kafka"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// startKafka consumes the stats produced to the Kafka topic, if set.
+func startKafka(conf config.Kafka) {
+	topic := conf.Topic
+	if topic == "" {
+		return
+	}
+	consumer, err := kafka.NewConsumer(conf.Brokers, topic, conf.Group)
+	if err != nil {
+		log.Fatal("Kafka: ", err)
+	}
+	log.Printf("Consuming stats from topic %s", topic)
+	go func() {
+		if err := consumer.Run(context.Background(), handleRecord); err != nil {
+			log.Fatal("Kafka: ", err)
+		}
+	}()
+}
+
+// handleRecord feeds the stats of a record to the exporter of its tenant. The record headers play
+// the role of the HTTP headers. Records of a tenant that can't be resolved are skipped.
+func handleRecord(r *kgo.Record) {
+	headers := make(map[string]string, len(r.Headers))
+	for _, h := range r.Headers {
+		headers[strings.ToLower(h.Key)] = string(h.Value)
+	}
+	tenant, err := tenantExps.resolve("", func(key string) string { return headers[strings.ToLower(key)] })
+	if err != nil {
+		log.Printf("Record %s[%d]@%d: %v", r.Topic, r.Partition, r.Offset, err)
+		return
+	}
+	exp, err := tenantExps.exporterOf(tenant)
+	if err != nil {
+		log.Printf("Record %s[%d]@%d: %v", r.Topic, r.Partition, r.Offset, err)
+		return
+	}
+	encoding := headers["content-encoding"]
+	if encoding == "" {
+		encoding = ingest.Sniff(r.Value)
+	}
+	body, err := ingest.NewBody(bytes.NewReader(r.Value), encoding, maxPayloadSize.Load())
+	if err != nil {
+		log.Printf("Record %s[%d]@%d: %v", r.Topic, r.Partition, r.Offset, err)
+		return
+	}
+	defer body.Close()
+	s, err := stats.Decode(body)
+	exp.CountIngest(body.Encoding, body.WireBytes(), body.DecodedBytes())
+	if err != nil {
+		log.Printf("Record %s[%d]@%d: %v", r.Topic, r.Partition, r.Offset, err)
+		return
+	}
+	info := prom.ClientInfo{
+		UserAgent:         headers["user-agent"],
+		LibrdkafkaVersion: headers[strings.ToLower(LIBRDKAFKA_VERSION_HEADER)],
+		LanguageVersion:   headers[strings.ToLower(LANGUAGE_VERSION_HEADER)],
+		GroupID:           headers[strings.ToLower(GROUP_ID_HEADER)],
+	}
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		log.Printf("Record %s[%d]@%d: %v", r.Topic, r.Partition, r.Offset, err)
+	}
+}
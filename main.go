@@ -1,67 +1,276 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
 	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var promExp *prom.PrometheusLibrdKafkaExporter
 
-const PORT = "PORT"
+// maxPayloadSize is the max decoded size of a stats payload, changed by reloads.
+var maxPayloadSize atomic.Int64
 
-func main() {
-	promExp = prom.NewPrometheusLibrdKafkaExporter()
+const (
+	LIBRDKAFKA_VERSION_HEADER = "X-Librdkafka-Version"
+	LANGUAGE_VERSION_HEADER   = "X-Language-Version"
+	GROUP_ID_HEADER           = "X-Group-Id"
+)
 
-	http.HandleFunc("/", requestHandler)
-	http.Handle("/metrics", promhttp.Handler())
+func main() {
+	configFile := flag.String("config", "", "Configuration file (.yaml, .toml or .json), overridden by the environment variables")
+	printDefaultConfig := flag.Bool("print-default-config", false, "Print the default configuration as YAML and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		config.Usage(flag.CommandLine.Output())
+	}
+	flag.Parse()
+	if *printDefaultConfig {
+		if err := config.Default().WriteYAML(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	port := os.Getenv(PORT)
+	conf, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	promExp, err = newExporter(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	maxPayloadSize.Store(conf.Limits.MaxPayloadSize)
+	tenantExps = newTenants(conf, promExp.Mappings, promExp.Settings)
+	reloader, err := newReloader(*configFile, conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go reloader.watchSignals()
 
-	if port == "" {
-		port = "8080"
+	if addr := conf.Ingest.UDPAddr; addr != "" {
+		go serveDatagrams("udp", addr)
+	}
+	if path := conf.Ingest.UnixSocket; path != "" {
+		go serveDatagrams("unixgram", path)
 	}
-	log.Println("Listening on port: ", port)
-	err := http.ListenAndServe(":"+port, nil)
+	if addr := conf.Ingest.GRPCAddr; addr != "" {
+		go serveGRPC(addr)
+	}
+	startOTLP(conf.OTLP)
+	startRemoteWrite(conf.RemoteWrite)
+	startKafka(conf.Ingest.Kafka)
+	startTail(conf.Ingest.Tail)
+
+	log.Println("Listening on: ", conf.ListenAddr())
+	err = http.ListenAndServe(conf.ListenAddr(), newMux(conf, reloader))
 	if err != nil {
 		log.Fatal("ListenAndServe: ", err)
 	}
 
 }
 
+// newMux routes the HTTP API.
+func newMux(conf *config.Config, reloader *reloader) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(conf.IngestPath, requestHandler)
+	mux.HandleFunc(config.BATCH_PATH, batchHandler)
+	mux.HandleFunc(PUSHGATEWAY_PATH, pushgatewayHandler)
+	mux.Handle(conf.MetricsPath, promhttp.InstrumentMetricHandler(promExp.Registry,
+		promhttp.HandlerFor(promExp.Registry, promhttp.HandlerOpts{})))
+	mux.HandleFunc(RELOAD_PATH, reloader.handler)
+	if tenantExps.enabled() {
+		mux.HandleFunc("POST "+TENANTS_PATH+"{tenant}/stats", tenantExps.statsHandler)
+		mux.HandleFunc("GET "+TENANTS_PATH+"{tenant}/metrics", tenantExps.metricsHandler)
+		mux.HandleFunc("GET "+TENANTS_PATH+"metrics", tenantExps.adminHandler)
+	}
+	return mux
+}
+
+// newExporter builds the exporter of the configured metrics.
+func newExporter(conf *config.Config) (*prom.PrometheusLibrdKafkaExporter, error) {
+	mappings, err := loadMappings(conf)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{
+		Prefix:      conf.Metrics.Prefix,
+		ConstLabels: prometheus.Labels(conf.Metrics.Labels),
+		Mappings:    mappings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exporter.Settings, err = settings(conf)
+	if err != nil {
+		return nil, err
+	}
+	return exporter, nil
+}
+
+// loadMappings loads the configured mappings, keeping the metric groups selected.
+func loadMappings(conf *config.Config) (*prom.Mappings, error) {
+	mappings := prom.DefaultMappings()
+	if path := conf.Metrics.MappingsFile; path != "" {
+		var err error
+		mappings, err = prom.LoadMappings(path)
+		if err != nil {
+			return nil, fmt.Errorf("mappings: %w", err)
+		}
+	}
+	if groups := conf.Metrics.Groups; len(groups) > 0 {
+		var err error
+		mappings, err = mappings.Select(groups)
+		if err != nil {
+			return nil, fmt.Errorf("metric groups: %w", err)
+		}
+	}
+	return mappings, nil
+}
+
+// settings returns the exporter settings of the configuration.
+func settings(conf *config.Config) (prom.Settings, error) {
+	relabel, err := prom.NewRelabeler(conf.Metrics.Relabel)
+	if err != nil {
+		return prom.Settings{}, fmt.Errorf("metrics.relabel: %w", err)
+	}
+	groups := conf.Metrics.Groups
+	return prom.Settings{
+		StatsInterval:        time.Duration(conf.Clients.StatsIntervalMs) * time.Millisecond,
+		GraceMultiplier:      conf.Clients.GraceMultiplier,
+		WindowStats:          conf.Metrics.WindowStats,
+		UnassignedPartitions: conf.Metrics.UnassignedPartitions,
+		ConsumerLag:          len(groups) == 0 || slices.Contains(groups, prom.LAG_GROUP),
+		Relabel:              relabel,
+		Limits: prom.Limits{
+			SeriesPerClient:    conf.Limits.MaxSeriesPerClient,
+			TopicsPerClient:    conf.Limits.MaxTopicsPerClient,
+			PartitionsPerTopic: conf.Limits.MaxPartitionsPerTopic,
+			Series:             conf.Limits.MaxSeries,
+		},
+	}, nil
+}
+
 func requestHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "POST":
-		handlePost(w, r)
+		if exp, ok := tenantExps.exporter(w, r); ok {
+			handlePost(w, r, exp)
+		}
 	default:
 		w.WriteHeader(http.StatusMethodNotAllowed)
 	}
 }
 
-func handlePost(w http.ResponseWriter, r *http.Request) {
+// handlePost applies the stats posted to the exporter, promExp or the exporter of a tenant.
+func handlePost(w http.ResponseWriter, r *http.Request, exp *prom.PrometheusLibrdKafkaExporter) {
 
 	defer r.Body.Close()
 	log.Println(">> Handling stats from requester:: ", r.Header.Get("User-Agent"))
-	stats := make(map[string]interface{})
-	err := json.NewDecoder(r.Body).Decode(&stats) // Pass a pointer to Stats
-	if err != nil {
-		log.Println(err)
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("ERROR"))
+	var s *stats.Stats
+	if !readPayload(w, r, exp, func(body io.Reader) (err error) {
+		s, err = stats.Decode(body)
+		return err
+	}) {
+		return
 	}
 
-	errUpd := promExp.UpdateStats(stats)
+	errUpd := exp.UpdateClientStats(s, clientInfo(r))
 	if errUpd != nil {
 		log.Println(errUpd)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("ERROR"))
+		writeUpdateError(w, errUpd)
+		return
 	}
 	log.Println("Request Completed")
 	w.WriteHeader(http.StatusOK) // 200
 	w.Write([]byte("OK"))
 
 }
+
+// writeUpdateError writes the status of a push the exporter failed to apply fully. A push partially
+// applied because of the series limits is 202 Accepted, a push rejected by the limits 429 Too Many Requests.
+// Stats without name or type are 400 Bad Request.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, stats.ErrNoClient) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	var limited *prom.LimitError
+	if !errors.As(err, &limited) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("ERROR"))
+		return
+	}
+	if limited.Rejected {
+		w.WriteHeader(http.StatusTooManyRequests)
+	} else {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	w.Write([]byte(err.Error()))
+}
+
+// readPayload decodes the request body, decompressing it according to its Content-Encoding.
+// On failure the error status is written and false returned.
+func readPayload(w http.ResponseWriter, r *http.Request, exp *prom.PrometheusLibrdKafkaExporter, decode func(io.Reader) error) bool {
+	body, err := ingest.NewBody(r.Body, r.Header.Get("Content-Encoding"), maxPayloadSize.Load())
+	if err != nil {
+		log.Println(err)
+		if errors.Is(err, ingest.ErrUnsupportedEncoding) {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte("ERROR"))
+		return false
+	}
+	defer body.Close()
+	err = decode(body)
+	if err == nil {
+		// Read the rest of the payload, so it is checked against the max size and counted.
+		_, err = io.Copy(io.Discard, body)
+	}
+	exp.CountIngest(body.Encoding, body.WireBytes(), body.DecodedBytes())
+	if err != nil {
+		log.Println(err)
+		if errors.Is(err, ingest.ErrTooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte("ERROR"))
+		return false
+	}
+	return true
+}
+
+// clientInfo describes the client behind a request.
+func clientInfo(r *http.Request) prom.ClientInfo {
+	remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteAddr = r.RemoteAddr
+	}
+	return prom.ClientInfo{
+		UserAgent:         r.Header.Get("User-Agent"),
+		RemoteAddr:        remoteAddr,
+		LibrdkafkaVersion: r.Header.Get(LIBRDKAFKA_VERSION_HEADER),
+		LanguageVersion:   r.Header.Get(LANGUAGE_VERSION_HEADER),
+		GroupID:           r.Header.Get(GROUP_ID_HEADER),
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+)
+
+// The stats without name or type are rejected by the exporter, whatever the endpoint.
+func TestPostRejectsStatsWithoutClient(t *testing.T) {
+	mux := startDefault(t)
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"POST", "/", `{}`, http.StatusBadRequest},
+		{"POST", "/", `{"name":"app#producer-1"}`, http.StatusBadRequest},
+		{"PUT", PUSHGATEWAY_PATH + "job/app", `{"type":"producer"}`, http.StatusBadRequest},
+		{"POST", "/", `{"name":"app#producer-1","type":"producer"}`, http.StatusOK},
+	} {
+		if status := serve(mux, tc.method, tc.path, []byte(tc.body)); status != tc.status {
+			t.Errorf("%s %s %s: got status %d, want %d", tc.method, tc.path, tc.body, status, tc.status)
+		}
+	}
+
+	req := []byte(`[{"name":"app#producer-1","type":"producer"}, {}]`)
+	rec := serveRecorder(mux, "POST", config.BATCH_PATH, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("batch: got status %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"accepted":1,"rejected":1`) || !strings.Contains(body, "without name or type") {
+		t.Errorf("batch: got %s", body)
+	}
+}
+
+// A push partially applied because of the series limits is accepted with 202, a push left without
+// any series rejected with 429, and the limits follow the reloads.
+func TestPostLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+limits:
+  max_series_per_client: 10
+`)
+	r := start(t, path)
+	conf, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := newMux(conf, r)
+	payload := loadStats(t)
+	other := renamed(t, payload, "rdkafka#producer-2")
+
+	rec := serveRecorder(mux, "POST", "/", payload)
+	if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), prom.LIMIT_SERIES_PER_CLIENT) {
+		t.Errorf("got status %d, %s, want %d", rec.Code, rec.Body, http.StatusAccepted)
+	}
+	rec = serveRecorder(mux, "POST", config.BATCH_PATH, append(append([]byte("["), payload...), ']'))
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, `"status":"PARTIAL"`) || !strings.Contains(body, `"accepted":1`) {
+		t.Errorf("batch: got status %d, %s", rec.Code, body)
+	}
+
+	writeConfig(t, path, `
+limits:
+  max_series: 10
+`)
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+	rec = serveRecorder(mux, "POST", "/", other)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), prom.LIMIT_SERIES) {
+		t.Errorf("got status %d, %s, want %d", rec.Code, rec.Body, http.StatusTooManyRequests)
+	}
+	rec = serveRecorder(mux, "POST", config.BATCH_PATH, append(append([]byte("["), other...), ']'))
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, `"status":"ERROR"`) || !strings.Contains(body, `"rejected":1`) {
+		t.Errorf("batch: got status %d, %s", rec.Code, body)
+	}
+	if status := serve(mux, "POST", "/", payload); status != http.StatusAccepted {
+		t.Errorf("got status %d, want %d", status, http.StatusAccepted)
+	}
+
+	writeConfig(t, path, "limits: {}\n")
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range [][]byte{other, payload} {
+		if status := serve(mux, "POST", "/", body); status != http.StatusOK {
+			t.Errorf("got status %d, want %d", status, http.StatusOK)
+		}
+	}
+}
+
+// The client info is taken from the headers and the remote address of the request.
+func TestPostClientInfo(t *testing.T) {
+	mux := startDefault(t)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(loadStats(t)))
+	req.RemoteAddr = "10.0.0.1:51234"
+	req.Header.Set("User-Agent", "confluent-kafka-go/2.3.0")
+	req.Header.Set(LIBRDKAFKA_VERSION_HEADER, "2.3.0")
+	req.Header.Set(LANGUAGE_VERSION_HEADER, "go1.22")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d", rec.Code)
+	}
+
+	mfs, err := promExp.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"client_id": "rdkafka", "name": "rdkafka#producer-1", "type": "producer",
+		"user_agent": "confluent-kafka-go/2.3.0", "remote_addr": "10.0.0.1", "librdkafka_version": "2.3.0", "language_version": "go1.22"}
+	for _, mf := range mfs {
+		if mf.GetName() != "librdkafka_client_info" {
+			continue
+		}
+		if len(mf.Metric) != 1 {
+			t.Fatalf("got %d client info series, want 1", len(mf.Metric))
+		}
+		got := make(map[string]string)
+		for _, l := range mf.Metric[0].Label {
+			got[l.GetName()] = l.GetValue()
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("got client info %v, want %v", got, want)
+		}
+		return
+	}
+	t.Error("client info not exported")
+}
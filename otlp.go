@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/otlp"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+)
+
+// startOTLP pushes the metrics to the OTLP endpoint, if set.
+func startOTLP(conf config.OTLP) {
+	endpoint := conf.Endpoint
+	if endpoint == "" {
+		return
+	}
+	exporter := &otlp.Exporter{
+		Endpoint:       endpoint,
+		Protocol:       conf.Protocol,
+		Interval:       time.Duration(conf.IntervalMs) * time.Millisecond,
+		Headers:        conf.Headers,
+		ResourceLabels: prom.ROOT_LABELS,
+		Gatherer:       promExp.Registry,
+	}
+	if err := exporter.Start(); err != nil {
+		log.Fatal("OTLP: ", err)
+	}
+	log.Printf("Pushing OTLP %s metrics to %s every %v", exporter.Protocol, endpoint, exporter.Interval)
+	go exporter.Run(context.Background())
+}
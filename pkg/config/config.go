@@ -0,0 +1,442 @@
+// Package config loads the exporter configuration from a YAML or TOML file and the environment.
+// Settings are read from the file over the defaults, then overridden by the environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/kafka"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/otlp"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/remotewrite"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+)
+
+// Paths served by the exporter besides the metrics and ingest paths. Paths ending with /
+// serve their whole subtree.
+const (
+	BATCH_PATH       = "/v1/stats/batch"
+	PUSHGATEWAY_PATH = "/metrics/"
+	RELOAD_PATH      = "/-/reload"
+	TENANTS_PATH     = "/tenants/"
+)
+
+var reservedPaths = []string{BATCH_PATH, PUSHGATEWAY_PATH, RELOAD_PATH, TENANTS_PATH}
+
+// Config is the configuration of the exporter.
+type Config struct {
+	// Listen is the address of the HTTP server, ":<Port>" when empty.
+	Listen      string      `yaml:"listen" toml:"listen" env:"LISTEN_ADDR" env-description:"Address the HTTP server listens on, :PORT when empty"`
+	Port        string      `yaml:"port" toml:"port" env:"PORT" env-description:"Port the HTTP server listens on"`
+	MetricsPath string      `yaml:"metrics_path" toml:"metrics_path" env:"METRICS_PATH" env-description:"Path of the Prometheus metrics"`
+	IngestPath  string      `yaml:"ingest_path" toml:"ingest_path" env:"INGEST_PATH" env-description:"Path the stats are posted to"`
+	Metrics     Metrics     `yaml:"metrics" toml:"metrics"`
+	Clients     Clients     `yaml:"clients" toml:"clients"`
+	Limits      Limits      `yaml:"limits" toml:"limits"`
+	Ingest      Ingest      `yaml:"ingest" toml:"ingest"`
+	OTLP        OTLP        `yaml:"otlp" toml:"otlp"`
+	RemoteWrite RemoteWrite `yaml:"remote_write" toml:"remote_write"`
+	Tenants     Tenants     `yaml:"tenants" toml:"tenants"`
+}
+
+// Metrics configures the metrics built from the stats.
+type Metrics struct {
+	Prefix string `yaml:"prefix" toml:"prefix" env:"METRICS_PREFIX" env-description:"Prefix of the metric names"`
+	Labels Pairs  `yaml:"labels" toml:"labels" env:"LABELS" env-description:"Labels added to every series, comma-separated name=value pairs"`
+	// Groups are the groups of metrics exported, all when empty.
+	Groups               []string `yaml:"groups" toml:"groups" env:"METRIC_GROUPS" env-description:"Metric groups exported, all when empty: brokers, topics, partitions, consumergroups, eos, lag..."`
+	MappingsFile         string   `yaml:"mappings_file" toml:"mappings_file" env:"MAPPINGS_FILE" env-description:"YAML or JSON file mapping the stats to metrics, the embedded mappings when empty"`
+	WindowStats          string   `yaml:"window_stats" toml:"window_stats" env:"WINDOW_STATS" env-description:"Window stats export: gauges, summary or native"`
+	UnassignedPartitions bool     `yaml:"unassigned_partitions" toml:"unassigned_partitions" env:"UNASSIGNED_PARTITIONS" env-description:"Export the internal unassigned partition (-1) of every topic"`
+	// Relabel are the rules rewriting and dropping the series, they can only be set in the file.
+	Relabel []prom.RelabelConfig `yaml:"relabel" toml:"relabel"`
+}
+
+// Clients configures how long the series of the clients are kept.
+type Clients struct {
+	StatsIntervalMs int     `yaml:"stats_interval_ms" toml:"stats_interval_ms" env:"STATS_INTERVAL_MS" env-description:"statistics.interval.ms assumed until the interval of a client is estimated"`
+	GraceMultiplier float64 `yaml:"grace_multiplier" toml:"grace_multiplier" env:"GRACE_MULTIPLIER" env-description:"Missed intervals after which a client and its series are dropped"`
+}
+
+// Limits bounds the resources used by the exporter.
+type Limits struct {
+	MaxPayloadSize int64 `yaml:"max_payload_size" toml:"max_payload_size" env:"MAX_PAYLOAD_SIZE" env-description:"Max size in bytes of a stats payload once decompressed"`
+	// The series limits are unlimited when zero.
+	MaxSeriesPerClient    int `yaml:"max_series_per_client" toml:"max_series_per_client" env:"MAX_SERIES_PER_CLIENT" env-description:"Max series of a client, unlimited when 0"`
+	MaxTopicsPerClient    int `yaml:"max_topics_per_client" toml:"max_topics_per_client" env:"MAX_TOPICS_PER_CLIENT" env-description:"Max topics of a client, unlimited when 0"`
+	MaxPartitionsPerTopic int `yaml:"max_partitions_per_topic" toml:"max_partitions_per_topic" env:"MAX_PARTITIONS_PER_TOPIC" env-description:"Max partitions of a topic of a client, unlimited when 0"`
+	MaxSeries             int `yaml:"max_series" toml:"max_series" env:"MAX_SERIES" env-description:"Max series of all the clients, unlimited when 0"`
+}
+
+// Ingest configures the transports the stats are received on, besides HTTP.
+type Ingest struct {
+	UDPAddr    string `yaml:"udp_addr" toml:"udp_addr" env:"UDP_ADDR" env-description:"Address stats datagrams are received on"`
+	UnixSocket string `yaml:"unix_socket" toml:"unix_socket" env:"UNIX_SOCKET" env-description:"Unix datagram socket stats are received on"`
+	GRPCAddr   string `yaml:"grpc_addr" toml:"grpc_addr" env:"GRPC_ADDR" env-description:"Address of the gRPC StatsService"`
+	Kafka      Kafka  `yaml:"kafka" toml:"kafka"`
+	Tail       Tail   `yaml:"tail" toml:"tail"`
+}
+
+// Kafka configures the consumption of the stats produced to a Kafka topic.
+type Kafka struct {
+	Brokers []string `yaml:"brokers" toml:"brokers" env:"KAFKA_BROKERS" env-description:"Kafka bootstrap brokers, comma-separated"`
+	Topic   string   `yaml:"topic" toml:"topic" env:"KAFKA_TOPIC" env-description:"Kafka topic the stats are consumed from"`
+	Group   string   `yaml:"group" toml:"group" env:"KAFKA_GROUP" env-description:"Kafka consumer group"`
+}
+
+// Tail configures the log files the stats are read from.
+type Tail struct {
+	Files  []string `yaml:"files" toml:"files" env:"TAIL_FILES" env-description:"Log files followed, comma-separated, - for stdin"`
+	Prefix string   `yaml:"prefix" toml:"prefix" env:"TAIL_PREFIX" env-description:"Regular expression matching the text logged before the stats"`
+}
+
+// OTLP configures the push of the metrics to an OpenTelemetry collector.
+type OTLP struct {
+	Endpoint   string `yaml:"endpoint" toml:"endpoint" env:"OTLP_ENDPOINT" env-description:"OTLP endpoint the metrics are pushed to"`
+	Protocol   string `yaml:"protocol" toml:"protocol" env:"OTLP_PROTOCOL" env-description:"OTLP protocol: http/protobuf or grpc"`
+	Headers    Pairs  `yaml:"headers" toml:"headers" env:"OTLP_HEADERS" env-description:"Headers of the OTLP requests, comma-separated name=value pairs"`
+	IntervalMs int    `yaml:"interval_ms" toml:"interval_ms" env:"OTLP_INTERVAL_MS" env-description:"Interval between two OTLP pushes"`
+}
+
+// RemoteWrite configures the Prometheus remote_write sender.
+type RemoteWrite struct {
+	URL            string `yaml:"url" toml:"url" env:"REMOTE_WRITE_URL" env-description:"Prometheus remote_write endpoint"`
+	IntervalMs     int    `yaml:"interval_ms" toml:"interval_ms" env:"REMOTE_WRITE_INTERVAL_MS" env-description:"Interval between two remote_write sends"`
+	ExternalLabels Pairs  `yaml:"external_labels" toml:"external_labels" env:"REMOTE_WRITE_EXTERNAL_LABELS" env-description:"Labels added to the series sent, comma-separated name=value pairs"`
+	Headers        Pairs  `yaml:"headers" toml:"headers" env:"REMOTE_WRITE_HEADERS" env-description:"Headers of the remote_write requests, comma-separated name=value pairs"`
+	QueueCapacity  int    `yaml:"queue_capacity" toml:"queue_capacity" env:"REMOTE_WRITE_QUEUE_CAPACITY" env-description:"Samples kept in memory while the endpoint is unreachable"`
+}
+
+// Tenants configures the tenants, each with its own exporter and metrics endpoint.
+type Tenants struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled" env:"TENANTS_ENABLED" env-description:"Serve an exporter per tenant on /tenants/{tenant}/stats and /tenants/{tenant}/metrics"`
+	Header  string `yaml:"header" toml:"header" env:"TENANT_HEADER" env-description:"Header naming the tenant of the stats pushed over HTTP, gRPC and Kafka"`
+	// Tokens maps the bearer tokens to their tenant.
+	Tokens     Pairs  `yaml:"tokens" toml:"tokens" env:"TENANT_TOKENS" env-description:"Bearer tokens of the tenants, comma-separated token=tenant pairs, required to access a tenant when set"`
+	AdminToken string `yaml:"admin_token" toml:"admin_token" env:"TENANT_ADMIN_TOKEN" env-description:"Bearer token of the /tenants/metrics endpoint of all the tenants, open when empty"`
+	MaxTenants int    `yaml:"max_tenants" toml:"max_tenants" env:"MAX_TENANTS" env-description:"Max tenants, unlimited when 0"`
+	// Limits replace the series limits for the listed tenants, they can only be set in the file.
+	Limits map[string]TenantLimits `yaml:"limits" toml:"limits"`
+}
+
+// TenantLimits are the series limits of a tenant, unlimited when zero.
+type TenantLimits struct {
+	MaxSeriesPerClient    int `yaml:"max_series_per_client" toml:"max_series_per_client"`
+	MaxTopicsPerClient    int `yaml:"max_topics_per_client" toml:"max_topics_per_client"`
+	MaxPartitionsPerTopic int `yaml:"max_partitions_per_topic" toml:"max_partitions_per_topic"`
+	MaxSeries             int `yaml:"max_series" toml:"max_series"`
+}
+
+// SeriesLimits returns the series limits of a tenant, the global ones unless its limits are configured.
+func (c *Config) SeriesLimits(tenant string) TenantLimits {
+	if limits, ok := c.Tenants.Limits[tenant]; ok {
+		return limits
+	}
+	return TenantLimits{
+		MaxSeriesPerClient:    c.Limits.MaxSeriesPerClient,
+		MaxTopicsPerClient:    c.Limits.MaxTopicsPerClient,
+		MaxPartitionsPerTopic: c.Limits.MaxPartitionsPerTopic,
+		MaxSeries:             c.Limits.MaxSeries,
+	}
+}
+
+var (
+	tenantName  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,63}$`)
+	validHeader = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$") // RFC 9110 token
+)
+
+// ValidTenant reports whether name is a valid tenant name: up to 64 letters, digits, _, . and -.
+func ValidTenant(name string) bool {
+	return tenantName.MatchString(name)
+}
+
+// Pairs are name=value pairs, set from a comma-separated list in the environment.
+type Pairs map[string]string
+
+// SetValue implements cleanenv.Setter.
+func (p *Pairs) SetValue(s string) error {
+	pairs := make(Pairs)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("invalid pair %q, expected name=value", pair)
+		}
+		pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	*p = pairs
+	return nil
+}
+
+// names returns the names of the pairs, sorted.
+func (p Pairs) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		Port:        "8080",
+		MetricsPath: "/metrics",
+		IngestPath:  "/",
+		Metrics: Metrics{
+			Prefix:      prom.PREFIX,
+			WindowStats: prom.WINDOW_GAUGES,
+		},
+		Clients: Clients{
+			StatsIntervalMs: int(prom.DEFAULT_STATS_INTERVAL / time.Millisecond),
+			GraceMultiplier: prom.DEFAULT_GRACE_MULTIPLIER,
+		},
+		Limits: Limits{
+			MaxPayloadSize: ingest.DEFAULT_MAX_SIZE,
+		},
+		Ingest: Ingest{
+			Kafka: Kafka{Group: kafka.DEFAULT_GROUP},
+		},
+		OTLP: OTLP{
+			Protocol:   otlp.PROTOCOL_HTTP,
+			IntervalMs: int(otlp.DEFAULT_INTERVAL / time.Millisecond),
+		},
+		RemoteWrite: RemoteWrite{
+			IntervalMs:    int(remotewrite.DEFAULT_INTERVAL / time.Millisecond),
+			QueueCapacity: remotewrite.DEFAULT_QUEUE_CAPACITY,
+		},
+		Tenants: Tenants{
+			Header:     "X-Tenant",
+			MaxTenants: 100,
+		},
+	}
+}
+
+// Load reads the configuration file at path, if any, then the environment variables, and validates the result.
+// The file format is taken from its extension: .yaml, .yml, .toml or .json.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	var err error
+	if path != "" {
+		err = cleanenv.ReadConfig(path, cfg)
+	} else {
+		err = cleanenv.ReadEnv(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// ListenAddr returns the address the HTTP server listens on.
+func (c *Config) ListenAddr() string {
+	if c.Listen != "" {
+		return c.Listen
+	}
+	return ":" + c.Port
+}
+
+// WriteYAML writes the configuration as YAML, e.g. to bootstrap a configuration file.
+func (c *Config) WriteYAML(w io.Writer) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Usage writes the environment variables of the configuration.
+func Usage(w io.Writer) {
+	header := "Environment variables:"
+	cleanenv.FUsage(w, &Config{}, &header)()
+}
+
+// Validate checks the configuration, reporting every problem found.
+func (c *Config) Validate() error {
+	var errs []error
+	errorf := func(path, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if _, port, err := net.SplitHostPort(c.ListenAddr()); err != nil {
+		errorf("listen", "%v", err)
+	} else if _, err := net.LookupPort("tcp", port); err != nil {
+		errorf("listen", "invalid port %q", port)
+	}
+	validPath(errorf, "metrics_path", c.MetricsPath)
+	validPath(errorf, "ingest_path", c.IngestPath)
+	if c.MetricsPath == c.IngestPath {
+		errorf("ingest_path", "%q is the metrics path", c.IngestPath)
+	}
+
+	if !model.IsValidMetricName(model.LabelValue(c.Metrics.Prefix + "metric")) {
+		errorf("metrics.prefix", "invalid metric name prefix %q", c.Metrics.Prefix)
+	}
+	for _, name := range c.Metrics.Labels.names() {
+		validLabel(errorf, "metrics.labels", name)
+		for _, root := range prom.ROOT_LABELS {
+			if name == root {
+				errorf("metrics.labels", "label %q is set from the stats", name)
+			}
+		}
+	}
+	switch c.Metrics.WindowStats {
+	case prom.WINDOW_GAUGES, prom.WINDOW_SUMMARY, prom.WINDOW_NATIVE:
+	default:
+		errorf("metrics.window_stats", "unknown mode %q, expected %s, %s or %s",
+			c.Metrics.WindowStats, prom.WINDOW_GAUGES, prom.WINDOW_SUMMARY, prom.WINDOW_NATIVE)
+	}
+	if groups := c.Metrics.Groups; len(groups) > 0 {
+		mappings := prom.DefaultMappings()
+		if path := c.Metrics.MappingsFile; path != "" {
+			var err error
+			if mappings, err = prom.LoadMappings(path); err != nil {
+				errorf("metrics.mappings_file", "%v", err)
+			}
+		}
+		if mappings != nil {
+			if _, err := mappings.Select(groups); err != nil {
+				errorf("metrics.groups", "%v", err)
+			}
+		}
+	}
+	for i, rule := range c.Metrics.Relabel {
+		if err := rule.Validate(); err != nil {
+			errorf(fmt.Sprintf("metrics.relabel[%d]", i), "%v", err)
+		}
+	}
+
+	if c.Clients.StatsIntervalMs <= 0 {
+		errorf("clients.stats_interval_ms", "must be positive")
+	}
+	if c.Clients.GraceMultiplier <= 0 {
+		errorf("clients.grace_multiplier", "must be positive")
+	}
+	if c.Limits.MaxPayloadSize <= 0 {
+		errorf("limits.max_payload_size", "must be positive")
+	}
+	for _, limit := range []struct {
+		path  string
+		value int
+	}{
+		{"limits.max_series_per_client", c.Limits.MaxSeriesPerClient},
+		{"limits.max_topics_per_client", c.Limits.MaxTopicsPerClient},
+		{"limits.max_partitions_per_topic", c.Limits.MaxPartitionsPerTopic},
+		{"limits.max_series", c.Limits.MaxSeries},
+	} {
+		if limit.value < 0 {
+			errorf(limit.path, "must not be negative")
+		}
+	}
+
+	if c.Ingest.Kafka.Topic != "" && len(c.Ingest.Kafka.Brokers) == 0 {
+		errorf("ingest.kafka.brokers", "required to consume topic %q", c.Ingest.Kafka.Topic)
+	}
+	for _, broker := range c.Ingest.Kafka.Brokers {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			errorf("ingest.kafka.brokers", "%v", err)
+		}
+	}
+	if _, err := regexp.Compile(c.Ingest.Tail.Prefix); err != nil {
+		errorf("ingest.tail.prefix", "%v", err)
+	}
+
+	if c.OTLP.Endpoint != "" {
+		if _, err := url.ParseRequestURI(c.OTLP.Endpoint); err != nil {
+			errorf("otlp.endpoint", "%v", err)
+		}
+		switch c.OTLP.Protocol {
+		case otlp.PROTOCOL_HTTP, otlp.PROTOCOL_GRPC:
+		default:
+			errorf("otlp.protocol", "unknown protocol %q, expected %s or %s", c.OTLP.Protocol, otlp.PROTOCOL_HTTP, otlp.PROTOCOL_GRPC)
+		}
+		if c.OTLP.IntervalMs <= 0 {
+			errorf("otlp.interval_ms", "must be positive")
+		}
+	}
+
+	if c.RemoteWrite.URL != "" {
+		if _, err := url.ParseRequestURI(c.RemoteWrite.URL); err != nil {
+			errorf("remote_write.url", "%v", err)
+		}
+		if c.RemoteWrite.IntervalMs <= 0 {
+			errorf("remote_write.interval_ms", "must be positive")
+		}
+		if c.RemoteWrite.QueueCapacity <= 0 {
+			errorf("remote_write.queue_capacity", "must be positive")
+		}
+		for _, name := range c.RemoteWrite.ExternalLabels.names() {
+			validLabel(errorf, "remote_write.external_labels", name)
+		}
+	}
+
+	if c.Tenants.Enabled && !validHeader.MatchString(c.Tenants.Header) {
+		errorf("tenants.header", "invalid header name %q", c.Tenants.Header)
+	}
+	for _, token := range c.Tenants.Tokens.names() {
+		if tenant := c.Tenants.Tokens[token]; !ValidTenant(tenant) {
+			errorf("tenants.tokens", "invalid tenant name %q", tenant)
+		}
+	}
+	if c.Tenants.MaxTenants < 0 {
+		errorf("tenants.max_tenants", "must not be negative")
+	}
+	tenants := make([]string, 0, len(c.Tenants.Limits))
+	for tenant := range c.Tenants.Limits {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	for _, tenant := range tenants {
+		path := "tenants.limits." + tenant
+		if !ValidTenant(tenant) {
+			errorf(path, "invalid tenant name %q", tenant)
+		}
+		limits := c.Tenants.Limits[tenant]
+		if limits.MaxSeriesPerClient < 0 || limits.MaxTopicsPerClient < 0 || limits.MaxPartitionsPerTopic < 0 || limits.MaxSeries < 0 {
+			errorf(path, "limits must not be negative")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validPath checks a path served by the exporter doesn't collide with the paths it reserves.
+func validPath(errorf func(path, format string, args ...interface{}), field, path string) {
+	if !strings.HasPrefix(path, "/") {
+		errorf(field, "%q must start with /", path)
+		return
+	}
+	if strings.ContainsAny(path, "{} ") {
+		errorf(field, "%q must not contain braces or spaces", path)
+		return
+	}
+	for _, reserved := range reservedPaths {
+		if path == reserved || strings.HasSuffix(reserved, "/") && strings.HasPrefix(path, reserved) {
+			errorf(field, "%q collides with %s, served by the exporter", path, reserved)
+		}
+	}
+}
+
+func validLabel(errorf func(path, format string, args ...interface{}), path, name string) {
+	if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+		errorf(path, "invalid label name %q", name)
+	}
+}
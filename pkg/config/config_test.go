@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		set   func(c *Config)
+		error string // empty when valid
+	}{
+		{"metrics path", func(c *Config) { c.MetricsPath = "/prometheus" }, ""},
+		{"metrics path under /metrics", func(c *Config) { c.MetricsPath = "/metrics/" }, "metrics_path"},
+		{"metrics path under the pushgateway", func(c *Config) { c.MetricsPath = "/metrics/job/app" }, "metrics_path"},
+		{"metrics path on reload", func(c *Config) { c.MetricsPath = "/-/reload" }, "metrics_path"},
+		{"ingest path on batch", func(c *Config) { c.IngestPath = "/v1/stats/batch" }, "ingest_path"},
+		{"ingest path under tenants", func(c *Config) { c.IngestPath = "/tenants/" }, "ingest_path"},
+		{"ingest path with wildcard", func(c *Config) { c.IngestPath = "/stats/{id}" }, "ingest_path"},
+		{"ingest path is metrics path", func(c *Config) { c.IngestPath = "/metrics" }, "ingest_path"},
+		{"relative path", func(c *Config) { c.IngestPath = "stats" }, "ingest_path"},
+		{"groups", func(c *Config) { c.Metrics.Groups = []string{"brokers", "lag"} }, ""},
+		{"unknown group", func(c *Config) { c.Metrics.Groups = []string{"brokers", "nope"} }, "metrics.groups"},
+		{"missing mappings file", func(c *Config) {
+			c.Metrics.Groups = []string{"brokers"}
+			c.Metrics.MappingsFile = "testdata/missing.yaml"
+		}, "metrics.mappings_file"},
+	} {
+		c := Default()
+		tc.set(c)
+		err := c.Validate()
+		switch {
+		case tc.error == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.error != "" && err == nil:
+			t.Errorf("%s: expected an error", tc.name)
+		case tc.error != "" && !strings.HasPrefix(err.Error(), tc.error+":"):
+			t.Errorf("%s: got error %v, want one on %s", tc.name, err, tc.error)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// Payloads larger than a datagram are split in chunks prefixed by the GELF chunk header:
+	// the magic bytes 0x1e 0x0f, an 8 bytes message id, the chunk sequence number and the chunk count.
+	CHUNK_MAGIC       = "\x1e\x0f"
+	CHUNK_HEADER_SIZE = 12
+	MAX_CHUNKS        = 128
+	CHUNK_TIMEOUT     = 5 * time.Second
+	MAX_PENDING       = 1024 // chunked payloads being reassembled at once
+
+	MAX_DATAGRAM_SIZE = 65535
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Sniff returns the encoding of a payload sent without headers, gzip and zstd being recognized by their magic bytes.
+func Sniff(payload []byte) string {
+	switch {
+	case bytes.HasPrefix(payload, gzipMagic):
+		return GZIP
+	case bytes.HasPrefix(payload, zstdMagic):
+		return ZSTD
+	}
+	return IDENTITY
+}
+
+// ListenDatagram listens for datagrams on a "udp" address or a "unixgram" socket path,
+// removing the stale socket file left by a previous run.
+func ListenDatagram(network, address string) (net.PacketConn, error) {
+	if network == "unixgram" {
+		if err := os.Remove(address); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return net.ListenPacket(network, address)
+}
+
+// ServeDatagrams reads one payload per datagram from conn, reassembling the chunked ones, and
+// calls handle for every complete payload. Reassembled payloads are limited to max bytes, read
+// on every datagram to follow the reloads. It returns when conn is closed.
+func ServeDatagrams(conn net.PacketConn, max func() int64, handle func(payload []byte, addr net.Addr)) error {
+	chunks := newReassembler(max)
+	buf := make([]byte, MAX_DATAGRAM_SIZE)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		datagram := buf[:n]
+		if !bytes.HasPrefix(datagram, []byte(CHUNK_MAGIC)) {
+			handle(bytes.Clone(datagram), addr)
+			continue
+		}
+		payload, err := chunks.add(datagram, addr, time.Now())
+		if err != nil {
+			log.Printf("Datagram from %v: %v", addr, err)
+			continue
+		}
+		if payload != nil {
+			handle(payload, addr)
+		}
+	}
+}
+
+type chunkedPayload struct {
+	chunks   [][]byte
+	received int
+	size     int64
+	first    time.Time
+}
+
+// reassembler collects the chunks of the payloads split over several datagrams. It is not safe for concurrent use.
+type reassembler struct {
+	pending map[string]*chunkedPayload // sender address and message id -> chunks
+	max     func() int64
+}
+
+func newReassembler(max func() int64) *reassembler {
+	return &reassembler{pending: make(map[string]*chunkedPayload), max: max}
+}
+
+// add stores a chunk, returning the payload once all its chunks are received.
+func (r *reassembler) add(datagram []byte, addr net.Addr, now time.Time) ([]byte, error) {
+	if len(datagram) < CHUNK_HEADER_SIZE {
+		return nil, errors.New("truncated chunk header")
+	}
+	id := binary.BigEndian.Uint64(datagram[2:10])
+	seq, count := int(datagram[10]), int(datagram[11])
+	if count == 0 || count > MAX_CHUNKS || seq >= count {
+		return nil, fmt.Errorf("invalid chunk %d of %d", seq, count)
+	}
+	key := fmt.Sprintf("%v/%x", addr, id)
+
+	r.expire(now)
+	p, ok := r.pending[key]
+	if !ok {
+		if len(r.pending) >= MAX_PENDING {
+			return nil, errors.New("too many chunked payloads pending")
+		}
+		p = &chunkedPayload{chunks: make([][]byte, count), first: now}
+		r.pending[key] = p
+	}
+	if len(p.chunks) != count {
+		delete(r.pending, key)
+		return nil, fmt.Errorf("chunk count changed from %d to %d", len(p.chunks), count)
+	}
+	if p.chunks[seq] != nil {
+		return nil, nil
+	}
+	chunk := datagram[CHUNK_HEADER_SIZE:]
+	p.size += int64(len(chunk))
+	if max := r.max(); max > 0 && p.size > max {
+		delete(r.pending, key)
+		return nil, ErrTooLarge
+	}
+	p.chunks[seq] = bytes.Clone(chunk)
+	p.received++
+	if p.received < count {
+		return nil, nil
+	}
+	delete(r.pending, key)
+	return bytes.Join(p.chunks, nil), nil
+}
+
+// expire drops the payloads whose chunks didn't all arrive within CHUNK_TIMEOUT.
+func (r *reassembler) expire(now time.Time) {
+	for key, p := range r.pending {
+		if now.Sub(p.first) > CHUNK_TIMEOUT {
+			log.Printf("Dropping incomplete chunked payload %s, %d of %d chunks received", key, p.received, len(p.chunks))
+			delete(r.pending, key)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chunk returns the datagram holding the chunk seq of count of the message id.
+func chunk(id uint64, seq, count int, data string) []byte {
+	datagram := []byte(CHUNK_MAGIC)
+	datagram = binary.BigEndian.AppendUint64(datagram, id)
+	datagram = append(datagram, byte(seq), byte(count))
+	return append(datagram, data...)
+}
+
+func TestReassembler(t *testing.T) {
+	var max atomic.Int64
+	max.Store(DEFAULT_MAX_SIZE)
+	r := newReassembler(max.Load)
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}
+	other := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1235}
+	now := time.Now()
+
+	add := func(datagram []byte, addr net.Addr, now time.Time) string {
+		t.Helper()
+		payload, err := r.add(datagram, addr, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(payload)
+	}
+
+	// Out of order, duplicated, and interleaved with the chunks of the same id from another sender.
+	for _, tc := range []struct {
+		datagram []byte
+		addr     net.Addr
+		payload  string
+	}{
+		{chunk(1, 2, 3, "c"), addr, ""},
+		{chunk(1, 0, 3, "a"), addr, ""},
+		{chunk(1, 0, 3, "x"), addr, ""},
+		{chunk(1, 0, 2, "y"), other, ""},
+		{chunk(1, 1, 3, "b"), addr, "abc"},
+		{chunk(1, 1, 2, "z"), other, "yz"},
+		{chunk(2, 0, 1, "single"), addr, "single"},
+	} {
+		if payload := add(tc.datagram, tc.addr, now); payload != tc.payload {
+			t.Errorf("got payload %q, want %q", payload, tc.payload)
+		}
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("got %d payloads pending, want 0", len(r.pending))
+	}
+
+	// The chunks of a payload not complete within CHUNK_TIMEOUT are dropped.
+	add(chunk(3, 0, 2, "a"), addr, now)
+	if payload := add(chunk(3, 1, 2, "b"), addr, now.Add(CHUNK_TIMEOUT+time.Second)); payload != "" {
+		t.Errorf("got payload %q of expired chunks", payload)
+	}
+	if payload := add(chunk(3, 0, 2, "a"), addr, now.Add(CHUNK_TIMEOUT+time.Second)); payload != "ab" {
+		t.Errorf("got payload %q, want %q", payload, "ab")
+	}
+
+	// The max size is read on every chunk.
+	max.Store(3)
+	add(chunk(4, 0, 2, "ab"), addr, now)
+	if _, err := r.add(chunk(4, 1, 2, "cd"), addr, now); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("got %v, want %v", err, ErrTooLarge)
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("got %d payloads pending after an oversize one, want 0", len(r.pending))
+	}
+	max.Store(4)
+	add(chunk(4, 0, 2, "ab"), addr, now)
+	if payload := add(chunk(4, 1, 2, "cd"), addr, now); payload != "abcd" {
+		t.Errorf("got payload %q, want %q", payload, "abcd")
+	}
+
+	for _, datagram := range [][]byte{
+		[]byte(CHUNK_MAGIC + "short"),
+		chunk(5, 0, 0, "a"),
+		chunk(5, 2, 2, "a"),
+		chunk(5, 0, MAX_CHUNKS+1, "a"),
+	} {
+		if _, err := r.add(datagram, addr, now); err == nil {
+			t.Errorf("%q: expected an error", datagram)
+		}
+	}
+	add(chunk(6, 0, 2, "a"), addr, now)
+	if _, err := r.add(chunk(6, 1, 3, "b"), addr, now); err == nil {
+		t.Error("changed chunk count: expected an error")
+	}
+}
+
+func TestServeDatagrams(t *testing.T) {
+	conn, err := ListenDatagram("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var max atomic.Int64
+	max.Store(DEFAULT_MAX_SIZE)
+	payloads := make(chan []byte, 10)
+	done := make(chan error)
+	go func() {
+		done <- ServeDatagrams(conn, max.Load, func(payload []byte, addr net.Addr) {
+			payloads <- payload
+		})
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	receive := func(want string) {
+		t.Helper()
+		select {
+		case payload := <-payloads:
+			if !bytes.Equal(payload, []byte(want)) {
+				t.Errorf("got payload %q, want %q", payload, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("payload %q not received", want)
+		}
+	}
+
+	for _, datagram := range [][]byte{[]byte("plain"), chunk(1, 1, 2, "def"), chunk(1, 0, 2, "abc")} {
+		if _, err := client.Write(datagram); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receive("plain")
+	receive("abcdef")
+
+	// A reload lowering the max size applies to the next datagrams.
+	max.Store(4)
+	for _, datagram := range [][]byte{chunk(2, 0, 2, "abc"), chunk(2, 1, 2, "def"), []byte("sent")} {
+		if _, err := client.Write(datagram); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receive("sent")
+
+	conn.Close()
+	if err := <-done; err != nil {
+		t.Errorf("got %v once closed", err)
+	}
+}
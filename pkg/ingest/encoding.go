@@ -0,0 +1,144 @@
+// Package ingest reads the librdkafka stats payloads received by the exporter transports.
+package ingest
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	IDENTITY = "identity"
+	GZIP     = "gzip"
+	DEFLATE  = "deflate"
+	ZSTD     = "zstd"
+
+	DEFAULT_MAX_SIZE = 32 << 20 // max decoded size of a payload, 32 MiB
+	MAX_ENCODINGS    = 2        // max number of encodings applied in sequence
+)
+
+// ErrTooLarge is returned when a decoded payload exceeds the max size.
+var ErrTooLarge = errors.New("stats payload too large")
+
+// ErrUnsupportedEncoding is returned for a content encoding other than gzip, deflate or zstd,
+// or for more than MAX_ENCODINGS encodings applied in sequence.
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// Body is a payload being decoded, counting the bytes read from the wire and the decoded ones.
+type Body struct {
+	// Encoding names the encodings of the payload, made of IDENTITY, GZIP, DEFLATE and ZSTD only,
+	// to be used as a label.
+	Encoding string
+	wire     *countingReader
+	decoded  *countingReader
+	closers  []io.Closer
+	max      int64
+}
+
+// NewBody decodes r according to a Content-Encoding header value, failing with ErrTooLarge
+// once more than max decoded bytes are read. Up to MAX_ENCODINGS encodings applied in sequence are
+// decoded in reverse order.
+func NewBody(r io.Reader, contentEncoding string, max int64) (*Body, error) {
+	b := &Body{Encoding: IDENTITY, wire: &countingReader{r: r}, max: max}
+	var reader io.Reader = b.wire
+	var encodings []string
+	for _, e := range strings.Split(contentEncoding, ",") {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" && e != IDENTITY {
+			if e == "x-gzip" {
+				e = GZIP
+			}
+			encodings = append(encodings, e)
+		}
+	}
+	if len(encodings) > MAX_ENCODINGS {
+		return nil, fmt.Errorf("%w: %d encodings applied in sequence, at most %d", ErrUnsupportedEncoding, len(encodings), MAX_ENCODINGS)
+	}
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch encodings[i] {
+		case GZIP:
+			var zr *gzip.Reader
+			if zr, err = gzip.NewReader(reader); err == nil {
+				reader = zr
+				b.closers = append(b.closers, zr)
+			}
+		case DEFLATE:
+			var zr io.ReadCloser
+			if zr, err = zlib.NewReader(reader); err == nil {
+				reader = zr
+				b.closers = append(b.closers, zr)
+			}
+		case ZSTD:
+			var zr *zstd.Decoder
+			if zr, err = zstd.NewReader(reader, zstd.WithDecoderConcurrency(1)); err == nil {
+				reader = zr
+				b.closers = append(b.closers, zr.IOReadCloser())
+			}
+		default:
+			err = fmt.Errorf("%w %q", ErrUnsupportedEncoding, encodings[i])
+		}
+		if err != nil {
+			b.Close()
+			if errors.Is(err, ErrUnsupportedEncoding) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("invalid %s payload: %w", encodings[i], err)
+		}
+	}
+	if len(encodings) > 0 {
+		b.Encoding = strings.Join(encodings, ",")
+	}
+	b.decoded = &countingReader{r: reader}
+	return b, nil
+}
+
+// Read implements io.Reader, returning the decoded payload.
+func (b *Body) Read(p []byte) (int, error) {
+	if b.max > 0 && b.decoded.n >= b.max {
+		// Probe one more byte to tell a payload of exactly max bytes from a larger one.
+		var probe [1]byte
+		if n, _ := b.decoded.Read(probe[:]); n > 0 {
+			return 0, ErrTooLarge
+		}
+		return 0, io.EOF
+	}
+	if b.max > 0 && int64(len(p)) > b.max-b.decoded.n {
+		p = p[:b.max-b.decoded.n]
+	}
+	return b.decoded.Read(p)
+}
+
+// Close releases the decoders.
+func (b *Body) Close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		errs = append(errs, b.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
+
+// WireBytes returns the number of bytes read from the wire so far.
+func (b *Body) WireBytes() int64 {
+	return b.wire.n
+}
+
+// DecodedBytes returns the number of decoded bytes read so far.
+func (b *Body) DecodedBytes() int64 {
+	return b.decoded.n
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// encode applies the encodings in sequence to payload.
+func encode(t *testing.T, payload []byte, encodings ...string) []byte {
+	t.Helper()
+	for _, e := range encodings {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch e {
+		case GZIP:
+			w = gzip.NewWriter(&buf)
+		case DEFLATE:
+			w = zlib.NewWriter(&buf)
+		case ZSTD:
+			var err error
+			if w, err = zstd.NewWriter(&buf); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			t.Fatalf("unknown encoding %s", e)
+		}
+		if _, err := w.Write(payload); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		payload = buf.Bytes()
+	}
+	return payload
+}
+
+func TestNewBody(t *testing.T) {
+	payload := []byte(`{"name":"app#producer-1","client_id":"app","type":"producer"}`)
+	for _, tc := range []struct {
+		header   string
+		body     []byte
+		encoding string
+	}{
+		{"", payload, IDENTITY},
+		{"identity", payload, IDENTITY},
+		{"gzip", encode(t, payload, GZIP), GZIP},
+		{"X-Gzip", encode(t, payload, GZIP), GZIP},
+		{"deflate", encode(t, payload, DEFLATE), DEFLATE},
+		{"zstd", encode(t, payload, ZSTD), ZSTD},
+		{"zstd, gzip", encode(t, payload, ZSTD, GZIP), "zstd,gzip"},
+		{"x-gzip, identity, gzip", encode(t, payload, GZIP, GZIP), "gzip,gzip"},
+	} {
+		body, err := NewBody(bytes.NewReader(tc.body), tc.header, DEFAULT_MAX_SIZE)
+		if err != nil {
+			t.Errorf("%q: %v", tc.header, err)
+			continue
+		}
+		decoded, err := io.ReadAll(body)
+		body.Close()
+		if err != nil || !bytes.Equal(decoded, payload) {
+			t.Errorf("%q: got %q, error %v", tc.header, decoded, err)
+		}
+		if body.Encoding != tc.encoding {
+			t.Errorf("%q: got encoding %s, want %s", tc.header, body.Encoding, tc.encoding)
+		}
+		if body.WireBytes() != int64(len(tc.body)) || body.DecodedBytes() != int64(len(payload)) {
+			t.Errorf("%q: got %d wire and %d decoded bytes, want %d and %d", tc.header, body.WireBytes(), body.DecodedBytes(), len(tc.body), len(payload))
+		}
+	}
+}
+
+func TestNewBodyUnsupportedEncoding(t *testing.T) {
+	payload := []byte(`{}`)
+	for _, header := range []string{
+		"br",
+		"gzip, compress",
+		"gzip, gzip, gzip",
+		"zstd,deflate,gzip,identity",
+	} {
+		if _, err := NewBody(bytes.NewReader(payload), header, DEFAULT_MAX_SIZE); !errors.Is(err, ErrUnsupportedEncoding) {
+			t.Errorf("%q: got %v, want %v", header, err, ErrUnsupportedEncoding)
+		}
+	}
+	if _, err := NewBody(bytes.NewReader(payload), "gzip", DEFAULT_MAX_SIZE); err == nil || errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("invalid gzip payload: got %v", err)
+	}
+}
+
+// A small payload decompressing to more than the max size is cut once the max size is read.
+func TestNewBodyTooLarge(t *testing.T) {
+	const max = 1 << 10
+	bomb := encode(t, make([]byte, 16<<20), GZIP, GZIP)
+	body, err := NewBody(bytes.NewReader(bomb), "gzip, gzip", max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if _, err := io.ReadAll(body); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("got %v, want %v", err, ErrTooLarge)
+	}
+	if body.DecodedBytes() > max+1 {
+		t.Errorf("got %d decoded bytes, want at most %d", body.DecodedBytes(), max+1)
+	}
+
+	// A payload of exactly the max size is accepted.
+	body, err = NewBody(bytes.NewReader(encode(t, make([]byte, max), ZSTD)), ZSTD, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if decoded, err := io.ReadAll(body); err != nil || len(decoded) != max {
+		t.Errorf("got %d bytes, error %v, want %d", len(decoded), err, max)
+	}
+}
@@ -0,0 +1,199 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+const TAIL_POLL_INTERVAL = 500 * time.Millisecond
+
+// LineParser extracts the stats object logged on a line, after the optional Prefix
+// (e.g. a timestamp and a log level) is stripped. Without Prefix, the object starts at the first '{'.
+type LineParser struct {
+	Prefix *regexp.Regexp
+}
+
+// Parse returns the stats of a line, nil for the lines holding no stats.
+func (p *LineParser) Parse(line []byte) (*stats.Stats, error) {
+	if p.Prefix != nil {
+		loc := p.Prefix.FindIndex(line)
+		if loc == nil || loc[0] != 0 {
+			return nil, nil
+		}
+		line = line[loc[1]:]
+	}
+	start := bytes.IndexByte(line, '{')
+	if start < 0 {
+		return nil, nil
+	}
+	s, err := stats.Unmarshal(line[start:])
+	if err != nil {
+		return nil, err
+	}
+	// librdkafka always names the client and its type, other JSON objects are not stats.
+	if s.Name == "" || s.Type == "" {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// lineReader splits a stream in lines, skipping the lines longer than max bytes.
+// A last line without newline is kept until the newline is read.
+type lineReader struct {
+	r       *bufio.Reader
+	pending []byte
+	max     int64
+	skip    bool // the current line is too long
+}
+
+func newLineReader(r io.Reader, max int64) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, 64<<10), max: max}
+}
+
+// next returns the next complete line, or io.EOF when there is none yet.
+func (l *lineReader) next() ([]byte, error) {
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		if !l.skip {
+			l.pending = append(l.pending, chunk...)
+		}
+		if l.max > 0 && int64(len(l.pending)) > l.max {
+			log.Printf("Skipping line longer than %d bytes", l.max)
+			l.pending, l.skip = l.pending[:0], true
+		}
+		switch {
+		case err == nil:
+			line := bytes.TrimRight(l.pending, "\r\n")
+			skip := l.skip
+			l.pending, l.skip = l.pending[:0:0], false
+			if skip {
+				continue
+			}
+			return line, nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+// drain calls handle for every line left until the end of the stream. The last line doesn't need a newline.
+func (l *lineReader) drain(handle func(line []byte)) error {
+	for {
+		line, err := l.next()
+		if errors.Is(err, io.EOF) {
+			if len(l.pending) > 0 && !l.skip {
+				handle(l.pending)
+			}
+			l.pending, l.skip = l.pending[:0:0], false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		handle(line)
+	}
+}
+
+// ReadLines calls handle for every line of r until its end. The last line doesn't need a newline.
+func ReadLines(r io.Reader, max int64, handle func(line []byte)) error {
+	return newLineReader(r, max).drain(handle)
+}
+
+// TailFile calls handle for every line appended to the file at path until ctx is done, starting
+// from its beginning or its end. A rotated file (renamed or removed and recreated at path) is
+// read until its end before the new file is followed from its beginning, a truncated file is
+// read again from its beginning. A file missing at start is waited for, and read from its beginning
+// once created.
+func TailFile(ctx context.Context, path string, fromStart bool, max int64, handle func(line []byte)) error {
+	ticker := time.NewTicker(TAIL_POLL_INTERVAL)
+	defer ticker.Stop()
+	f, info, err := openTail(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("%s doesn't exist, waiting for it", path)
+		fromStart = true
+	}
+	for errors.Is(err, fs.ErrNotExist) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		f, info, err = openTail(path)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	if !fromStart {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	lines := newLineReader(f, max)
+	for {
+		line, err := lines.next()
+		if err == nil {
+			handle(line)
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := os.Stat(path)
+		if err != nil {
+			continue // rotated, the new file is not created yet
+		}
+		pos, _ := f.Seek(0, io.SeekCurrent)
+		switch {
+		case !os.SameFile(info, current):
+			// The lines written to the old file since it was last read are handled before following the new one.
+			if err := lines.drain(handle); err != nil {
+				return err
+			}
+			next, nextInfo, err := openTail(path)
+			if err != nil {
+				continue // removed again, retried on the next tick
+			}
+			log.Printf("%s rotated, reopening", path)
+			f.Close()
+			f, info = next, nextInfo
+			lines = newLineReader(f, max)
+		case current.Size() < pos:
+			log.Printf("%s truncated, reading from the beginning", path)
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			lines = newLineReader(f, max)
+		}
+	}
+}
+
+func openTail(path string) (*os.File, os.FileInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
@@ -0,0 +1,147 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+const statsLine = `{"name":"app#producer-%d","client_id":"app","type":"producer","ts":1}`
+
+func TestLineParser(t *testing.T) {
+	parser := &LineParser{Prefix: regexp.MustCompile(`^\S+ STATS `)}
+	for _, tc := range []struct {
+		line  string
+		name  string // empty when the line holds no stats
+		error bool
+	}{
+		{"2024-01-01T00:00:00Z STATS " + fmt.Sprintf(statsLine, 1), "app#producer-1", false},
+		{"2024-01-01T00:00:00Z INFO " + fmt.Sprintf(statsLine, 1), "", false},
+		{"2024-01-01T00:00:00Z STATS no stats here", "", false},
+		{`2024-01-01T00:00:00Z STATS {"level":"info","msg":"started"}`, "", false},
+		{`2024-01-01T00:00:00Z STATS {"name":"api","msg":"started"}`, "", false},
+		{`2024-01-01T00:00:00Z STATS {"name":`, "", true},
+	} {
+		s, err := parser.Parse([]byte(tc.line))
+		switch {
+		case tc.error && err == nil:
+			t.Errorf("%s: expected an error", tc.line)
+		case !tc.error && err != nil:
+			t.Errorf("%s: %v", tc.line, err)
+		case tc.name == "" && s != nil:
+			t.Errorf("%s: got stats %s, want none", tc.line, s.Name)
+		case tc.name != "" && (s == nil || s.Name != tc.name):
+			t.Errorf("%s: got stats %v, want %s", tc.line, s, tc.name)
+		}
+	}
+
+	// Without prefix, the stats start at the first '{' and other JSON log lines are skipped.
+	parser = &LineParser{}
+	if s, err := parser.Parse([]byte("stats: " + fmt.Sprintf(statsLine, 2))); err != nil || s == nil || s.Name != "app#producer-2" {
+		t.Errorf("got stats %v, error %v", s, err)
+	}
+	if s, err := parser.Parse([]byte(`{"level":"info","msg":"started","ts":1}`)); err != nil || s != nil {
+		t.Errorf("JSON log line: got stats %v, error %v", s, err)
+	}
+}
+
+// tail follows the file at path, returning the lines handled so far.
+func tail(t *testing.T, path string) func() []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	var mutex sync.Mutex
+	var lines []string
+	done := make(chan error)
+	go func() {
+		done <- TailFile(ctx, path, false, 0, func(line []byte) {
+			mutex.Lock()
+			lines = append(lines, string(line))
+			mutex.Unlock()
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+	return func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), lines...)
+	}
+}
+
+// waitLines waits for the tail to handle want.
+func waitLines(t *testing.T, lines func() []string, want ...string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for fmt.Sprint(lines()) != fmt.Sprint(want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got lines %q, want %q", lines(), want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTailFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLines(t, path, "before")
+	lines := tail(t, path)
+	time.Sleep(TAIL_POLL_INTERVAL / 2)
+
+	appendLines(t, path, "a")
+	waitLines(t, lines, "a")
+
+	// Lines written right before the rotation are read from the rotated file.
+	appendLines(t, path, "b")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLines(t, path, "c")
+	waitLines(t, lines, "a", "b", "c")
+}
+
+func TestTailFileTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLines(t, path)
+	lines := tail(t, path)
+	time.Sleep(TAIL_POLL_INTERVAL / 2)
+
+	appendLines(t, path, "a long line", "another long line")
+	waitLines(t, lines, "a long line", "another long line")
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * TAIL_POLL_INTERVAL)
+	appendLines(t, path, "b")
+	waitLines(t, lines, "a long line", "another long line", "b")
+}
+
+// A file missing at start is followed once created, from its beginning.
+func TestTailFileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	lines := tail(t, path)
+	time.Sleep(TAIL_POLL_INTERVAL)
+	appendLines(t, path, "a", "b")
+	waitLines(t, lines, "a", "b")
+}
@@ -0,0 +1,72 @@
+// Package kafka consumes the librdkafka stats that clients produce to a Kafka topic.
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	DEFAULT_GROUP    = "librdkafka-prometheus-exporter"
+	CLIENT_ID        = "librdkafka-prometheus-exporter"
+	MAX_POLL_RECORDS = 500
+)
+
+// Consumer reads the records of a stats topic as a member of a consumer group.
+// Offsets are committed only once the polled records are processed, so a restart
+// of the exporter replays the stats it didn't process rather than losing them.
+type Consumer struct {
+	client *kgo.Client
+}
+
+// NewConsumer joins group to consume topic from the given brokers. Extra client options,
+// such as TLS or SASL, are applied last.
+func NewConsumer(brokers []string, topic, group string, opts ...kgo.Opt) (*Consumer, error) {
+	if len(brokers) == 0 || topic == "" {
+		return nil, errors.New("kafka brokers and topic are required")
+	}
+	if group == "" {
+		group = DEFAULT_GROUP
+	}
+	client, err := kgo.NewClient(append([]kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.ClientID(CLIENT_ID),
+		kgo.ConsumerGroup(group),
+		kgo.ConsumeTopics(topic),
+		kgo.DisableAutoCommit(),
+		// Keep the partitions until the processed records are committed.
+		kgo.BlockRebalanceOnPoll(),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{client: client}, nil
+}
+
+// Run hands every record to handle and commits its offset afterwards, until ctx is done
+// or the consumer closed. Records handle fails on are logged by handle and committed too,
+// a payload that can't be decoded won't decode any better on a retry.
+func (c *Consumer) Run(ctx context.Context, handle func(*kgo.Record)) error {
+	for {
+		fetches := c.client.PollRecords(ctx, MAX_POLL_RECORDS)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			log.Printf("Kafka fetch %s[%d]: %v", topic, partition, err)
+		})
+		fetches.EachRecord(handle)
+		if err := c.client.CommitUncommittedOffsets(ctx); err != nil {
+			log.Println("Kafka commit: ", err)
+		}
+		c.client.AllowRebalance()
+	}
+}
+
+// Close leaves the group.
+func (c *Consumer) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	testTopic = "librdkafka-stats"
+	testGroup = "exporter-test"
+)
+
+func newCluster(t *testing.T) []string {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cluster.Close)
+	return cluster.ListenAddrs()
+}
+
+func produce(t *testing.T, client *kgo.Client, values ...string) {
+	t.Helper()
+	for _, v := range values {
+		if err := client.ProduceSync(context.Background(), &kgo.Record{Topic: testTopic, Value: []byte(v)}).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// committed returns the offset committed by the group for the partition 0 of the topic.
+func committed(t *testing.T, client *kgo.Client) int64 {
+	t.Helper()
+	req := kmsg.NewPtrOffsetFetchRequest()
+	req.Group = testGroup
+	topic := kmsg.NewOffsetFetchRequestTopic()
+	topic.Topic = testTopic
+	topic.Partitions = []int32{0}
+	req.Topics = append(req.Topics, topic)
+	res, err := req.RequestWith(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range res.Topics {
+		for _, p := range topic.Partitions {
+			return p.Offset
+		}
+	}
+	return -1
+}
+
+// consume runs a consumer of the group until its committed offset reaches offset, returning the records it handled.
+func consume(t *testing.T, brokers []string, admin *kgo.Client, offset int64) []string {
+	t.Helper()
+	consumer, err := NewConsumer(brokers, testTopic, testGroup, kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var mutex sync.Mutex
+	var handled []string
+	done := make(chan error)
+	go func() {
+		done <- consumer.Run(ctx, func(r *kgo.Record) {
+			mutex.Lock()
+			handled = append(handled, string(r.Value))
+			mutex.Unlock()
+		})
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	for committed(t, admin) != offset {
+		if time.Now().After(deadline) {
+			t.Fatalf("offset %d not committed, committed %d", offset, committed(t, admin))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	consumer.Close()
+	mutex.Lock()
+	defer mutex.Unlock()
+	return handled
+}
+
+func TestConsumerCommitsHandledRecords(t *testing.T) {
+	brokers := newCluster(t)
+	admin, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+
+	produce(t, admin, "a", "b", "c")
+	if got := fmt.Sprint(consume(t, brokers, admin, 3)); got != "[a b c]" {
+		t.Errorf("got records %s, want [a b c]", got)
+	}
+
+	// The committed records are not consumed again by the group.
+	produce(t, admin, "d")
+	if got := fmt.Sprint(consume(t, brokers, admin, 4)); got != "[d]" {
+		t.Errorf("after restart: got records %s, want [d]", got)
+	}
+}
@@ -0,0 +1,314 @@
+package otlp
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const (
+	SCOPE_NAME = "mcolomerc/librdkafka-prometheus-exporter"
+	// RESOURCE_PREFIX prefixes the resource attributes taken from the client labels.
+	RESOURCE_PREFIX = "librdkafka."
+	// SERVICE_NAME is the service.name of the metrics of the exporter itself.
+	SERVICE_NAME = "librdkafka-prometheus-exporter"
+)
+
+// Convert translates gathered Prometheus metrics into an OTLP export request. Every client,
+// identified by the values of resourceLabels, becomes a resource with a librdkafka.<label>
+// attribute per label. Metrics without those labels are reported under the exporter resource.
+// Counters, summaries and histograms are cumulative since their start time in starts.
+func Convert(families []*dto.MetricFamily, resourceLabels []string, starts *StartTimes, now time.Time) *colmetricspb.ExportMetricsServiceRequest {
+	c := &converter{
+		resourceLabels: resourceLabels,
+		resources:      make(map[string]*resource),
+		starts:         starts,
+		now:            uint64(now.UnixNano()),
+	}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			c.add(mf, m)
+		}
+	}
+	starts.prune(c.now)
+	return c.request()
+}
+
+type converter struct {
+	resourceLabels []string
+	resources      map[string]*resource
+	starts         *StartTimes
+	now            uint64
+}
+
+// StartTimes tracks the start times of the cumulative series across conversions: the time a
+// series was first converted, or was reset, its value or count going backwards.
+type StartTimes struct {
+	series map[string]*seriesStart
+}
+
+type seriesStart struct {
+	start, seen uint64
+	value       float64
+}
+
+// NewStartTimes returns start times tracking no series.
+func NewStartTimes() *StartTimes {
+	return &StartTimes{series: make(map[string]*seriesStart)}
+}
+
+// start returns the start time of a series having value at now.
+func (t *StartTimes) start(key string, value float64, now uint64) uint64 {
+	s, ok := t.series[key]
+	if !ok || value < s.value {
+		s = &seriesStart{start: now}
+		t.series[key] = s
+	}
+	s.seen, s.value = now, value
+	return s.start
+}
+
+// prune forgets the series not converted at now, they start again if they come back.
+func (t *StartTimes) prune(now uint64) {
+	for key, s := range t.series {
+		if s.seen != now {
+			delete(t.series, key)
+		}
+	}
+}
+
+// seriesKey identifies a series of a resource.
+func seriesKey(res *resource, name string, attributes []*commonpb.KeyValue) string {
+	var b strings.Builder
+	for _, a := range res.attributes {
+		b.WriteString(a.GetValue().GetStringValue())
+		b.WriteByte('/')
+	}
+	b.WriteString(name)
+	for _, a := range attributes {
+		b.WriteByte('/')
+		b.WriteString(a.GetKey())
+		b.WriteByte('=')
+		b.WriteString(a.GetValue().GetStringValue())
+	}
+	return b.String()
+}
+
+type resource struct {
+	attributes []*commonpb.KeyValue
+	metrics    map[string]*metricspb.Metric
+	names      []string // metric names in the gathering order
+}
+
+// resource returns the resource of a metric and the attributes of its data point.
+func (c *converter) resource(labels []*dto.LabelPair) (*resource, []*commonpb.KeyValue) {
+	values := make([]string, len(c.resourceLabels))
+	var attributes []*commonpb.KeyValue
+	found := false
+	for _, l := range labels {
+		i := indexOf(c.resourceLabels, l.GetName())
+		if i < 0 {
+			attributes = append(attributes, stringAttribute(l.GetName(), l.GetValue()))
+			continue
+		}
+		values[i], found = l.GetValue(), true
+	}
+	key := ""
+	if found {
+		key = "client/" + strings.Join(values, "/")
+	}
+	res, ok := c.resources[key]
+	if !ok {
+		res = &resource{metrics: make(map[string]*metricspb.Metric)}
+		if found {
+			for i, name := range c.resourceLabels {
+				res.attributes = append(res.attributes, stringAttribute(RESOURCE_PREFIX+name, values[i]))
+			}
+		} else {
+			res.attributes = []*commonpb.KeyValue{stringAttribute("service.name", SERVICE_NAME)}
+		}
+		c.resources[key] = res
+	}
+	return res, attributes
+}
+
+func (c *converter) add(mf *dto.MetricFamily, m *dto.Metric) {
+	res, attributes := c.resource(m.GetLabel())
+	metric, ok := res.metrics[mf.GetName()]
+	if !ok {
+		metric = &metricspb.Metric{Name: mf.GetName(), Description: mf.GetHelp()}
+		res.metrics[mf.GetName()] = metric
+		res.names = append(res.names, mf.GetName())
+	}
+	key := seriesKey(res, mf.GetName(), attributes)
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		sum := metric.GetSum()
+		if sum == nil {
+			sum = &metricspb.Sum{AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, IsMonotonic: true}
+			metric.Data = &metricspb.Metric_Sum{Sum: sum}
+		}
+		sum.DataPoints = append(sum.DataPoints, c.number(attributes, m.GetCounter().GetValue(), c.starts.start(key, m.GetCounter().GetValue(), c.now)))
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		if summary == nil {
+			summary = &metricspb.Summary{}
+			metric.Data = &metricspb.Metric_Summary{Summary: summary}
+		}
+		summary.DataPoints = append(summary.DataPoints, c.summary(attributes, m.GetSummary(), key))
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		h := m.GetHistogram()
+		if h.Schema != nil {
+			exp := metric.GetExponentialHistogram()
+			if exp == nil {
+				exp = &metricspb.ExponentialHistogram{AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE}
+				metric.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: exp}
+			}
+			exp.DataPoints = append(exp.DataPoints, c.exponentialHistogram(attributes, h, key))
+			return
+		}
+		hist := metric.GetHistogram()
+		if hist == nil {
+			hist = &metricspb.Histogram{AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE}
+			metric.Data = &metricspb.Metric_Histogram{Histogram: hist}
+		}
+		hist.DataPoints = append(hist.DataPoints, c.histogram(attributes, h, key))
+	default: // gauges and untyped metrics
+		gauge := metric.GetGauge()
+		if gauge == nil {
+			gauge = &metricspb.Gauge{}
+			metric.Data = &metricspb.Metric_Gauge{Gauge: gauge}
+		}
+		value := m.GetGauge().GetValue()
+		if m.Untyped != nil {
+			value = m.GetUntyped().GetValue()
+		}
+		gauge.DataPoints = append(gauge.DataPoints, c.number(attributes, value, 0))
+	}
+}
+
+func (c *converter) number(attributes []*commonpb.KeyValue, value float64, start uint64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: start,
+		TimeUnixNano:      c.now,
+		Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+func (c *converter) summary(attributes []*commonpb.KeyValue, s *dto.Summary, key string) *metricspb.SummaryDataPoint {
+	p := &metricspb.SummaryDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: c.starts.start(key, float64(s.GetSampleCount()), c.now),
+		TimeUnixNano:      c.now,
+		Count:             s.GetSampleCount(),
+		Sum:               s.GetSampleSum(),
+	}
+	for _, q := range s.GetQuantile() {
+		p.QuantileValues = append(p.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+			Quantile: q.GetQuantile(),
+			Value:    q.GetValue(),
+		})
+	}
+	return p
+}
+
+// exponentialHistogram translates a native histogram. The Prometheus bucket i covers
+// (base^(i-1), base^i] while the OTLP bucket i covers (base^i, base^(i+1)], hence the offset shift.
+func (c *converter) exponentialHistogram(attributes []*commonpb.KeyValue, h *dto.Histogram, key string) *metricspb.ExponentialHistogramDataPoint {
+	sum := h.GetSampleSum()
+	return &metricspb.ExponentialHistogramDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: c.starts.start(key, float64(h.GetSampleCount()), c.now),
+		TimeUnixNano:      c.now,
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+		Scale:             h.GetSchema(),
+		ZeroCount:         h.GetZeroCount(),
+		ZeroThreshold:     h.GetZeroThreshold(),
+		Positive:          exponentialBuckets(h.GetPositiveSpan(), h.GetPositiveDelta()),
+		Negative:          exponentialBuckets(h.GetNegativeSpan(), h.GetNegativeDelta()),
+	}
+}
+
+func exponentialBuckets(spans []*dto.BucketSpan, deltas []int64) *metricspb.ExponentialHistogramDataPoint_Buckets {
+	if len(spans) == 0 {
+		return nil
+	}
+	buckets := &metricspb.ExponentialHistogramDataPoint_Buckets{Offset: spans[0].GetOffset() - 1}
+	var count int64
+	d := 0
+	for i, span := range spans {
+		if i > 0 {
+			for gap := int32(0); gap < span.GetOffset(); gap++ {
+				buckets.BucketCounts = append(buckets.BucketCounts, 0)
+			}
+		}
+		for n := uint32(0); n < span.GetLength() && d < len(deltas); n++ {
+			count += deltas[d]
+			d++
+			buckets.BucketCounts = append(buckets.BucketCounts, uint64(count))
+		}
+	}
+	return buckets
+}
+
+func (c *converter) histogram(attributes []*commonpb.KeyValue, h *dto.Histogram, key string) *metricspb.HistogramDataPoint {
+	sum := h.GetSampleSum()
+	p := &metricspb.HistogramDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: c.starts.start(key, float64(h.GetSampleCount()), c.now),
+		TimeUnixNano:      c.now,
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+	}
+	var prev uint64
+	for _, b := range h.GetBucket() {
+		p.ExplicitBounds = append(p.ExplicitBounds, b.GetUpperBound())
+		p.BucketCounts = append(p.BucketCounts, b.GetCumulativeCount()-prev)
+		prev = b.GetCumulativeCount()
+	}
+	// The last OTLP bucket counts the values above the last bound.
+	p.BucketCounts = append(p.BucketCounts, h.GetSampleCount()-prev)
+	return p
+}
+
+func (c *converter) request() *colmetricspb.ExportMetricsServiceRequest {
+	keys := make([]string, 0, len(c.resources))
+	for key := range c.resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	req := &colmetricspb.ExportMetricsServiceRequest{}
+	for _, key := range keys {
+		res := c.resources[key]
+		scope := &metricspb.ScopeMetrics{Scope: &commonpb.InstrumentationScope{Name: SCOPE_NAME}}
+		for _, name := range res.names {
+			scope.Metrics = append(scope.Metrics, res.metrics[name])
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, &metricspb.ResourceMetrics{
+			Resource:     &resourcepb.Resource{Attributes: res.attributes},
+			ScopeMetrics: []*metricspb.ScopeMetrics{scope},
+		})
+	}
+	return req
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,157 @@
+// Package otlp pushes the exporter metrics to an OpenTelemetry collector over OTLP/HTTP or OTLP/gRPC.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	PROTOCOL_HTTP = "http/protobuf"
+	PROTOCOL_GRPC = "grpc"
+
+	DEFAULT_INTERVAL = 15 * time.Second
+	DEFAULT_TIMEOUT  = 10 * time.Second
+)
+
+// Exporter pushes the gathered metrics to an OTLP endpoint on every interval.
+type Exporter struct {
+	// Endpoint is the URL of the OTLP/HTTP metrics endpoint (http://collector:4318/v1/metrics)
+	// or the OTLP/gRPC address (http://collector:4317, https:// for TLS).
+	Endpoint string
+	Protocol string
+	Interval time.Duration
+	Timeout  time.Duration
+	// Headers are sent with every request, as HTTP headers or gRPC metadata.
+	Headers map[string]string
+	// ResourceLabels are the labels turned into resource attributes.
+	ResourceLabels []string
+	Gatherer       prometheus.Gatherer
+
+	mutex  sync.Mutex
+	starts *StartTimes
+	client *http.Client
+	grpc   colmetricspb.MetricsServiceClient
+}
+
+// Start validates the configuration and connects to a gRPC endpoint.
+func (e *Exporter) Start() error {
+	e.starts = NewStartTimes()
+	if e.Interval <= 0 {
+		e.Interval = DEFAULT_INTERVAL
+	}
+	if e.Timeout <= 0 {
+		e.Timeout = DEFAULT_TIMEOUT
+	}
+	switch e.Protocol {
+	case PROTOCOL_HTTP, "http", "":
+		e.Protocol = PROTOCOL_HTTP
+		e.client = &http.Client{Timeout: e.Timeout}
+	case PROTOCOL_GRPC:
+		target, creds := e.Endpoint, insecure.NewCredentials()
+		switch {
+		case strings.HasPrefix(target, "https://"):
+			target, creds = strings.TrimPrefix(target, "https://"), credentials.NewTLS(&tls.Config{})
+		case strings.HasPrefix(target, "http://"):
+			target = strings.TrimPrefix(target, "http://")
+		}
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		e.grpc = colmetricspb.NewMetricsServiceClient(conn)
+	default:
+		return fmt.Errorf("unknown OTLP protocol %q, expected %s or %s", e.Protocol, PROTOCOL_HTTP, PROTOCOL_GRPC)
+	}
+	return nil
+}
+
+// Run pushes the metrics on every interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Push(ctx); err != nil {
+				log.Println("OTLP push: ", err)
+			}
+		}
+	}
+}
+
+// Push gathers the metrics and sends them once.
+func (e *Exporter) Push(ctx context.Context) error {
+	families, err := e.Gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	e.mutex.Lock()
+	req := Convert(families, e.ResourceLabels, e.starts, time.Now())
+	e.mutex.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+	if e.grpc != nil {
+		if len(e.Headers) > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, metadata.New(e.Headers))
+		}
+		res, err := e.grpc.Export(ctx, req)
+		if err != nil {
+			return err
+		}
+		if rejected := res.GetPartialSuccess().GetRejectedDataPoints(); rejected > 0 {
+			return fmt.Errorf("%d data points rejected: %s", rejected, res.GetPartialSuccess().GetErrorMessage())
+		}
+		return nil
+	}
+	return e.pushHTTP(ctx, req)
+}
+
+func (e *Exporter) pushHTTP(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	res, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", res.Status, data)
+	}
+	var resp colmetricspb.ExportMetricsServiceResponse
+	if err := proto.Unmarshal(data, &resp); err == nil {
+		if rejected := resp.GetPartialSuccess().GetRejectedDataPoints(); rejected > 0 {
+			return fmt.Errorf("%d data points rejected: %s", rejected, resp.GetPartialSuccess().GetErrorMessage())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// receiver is a local OTLP/HTTP endpoint recording the requests it receives.
+func receiver(t *testing.T) (*httptest.Server, <-chan *colmetricspb.ExportMetricsServiceRequest) {
+	t.Helper()
+	requests := make(chan *colmetricspb.ExportMetricsServiceRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		req := &colmetricspb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- req
+		data, _ := proto.Marshal(&colmetricspb.ExportMetricsServiceResponse{})
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func counter(name, client, topic string, value float64) *dto.MetricFamily {
+	label := func(name, value string) *dto.LabelPair { return &dto.LabelPair{Name: &name, Value: &value} }
+	return &dto.MetricFamily{
+		Name: &name,
+		Type: dto.MetricType_COUNTER.Enum(),
+		Metric: []*dto.Metric{{
+			Label:   []*dto.LabelPair{label("client_id", client), label("topic", topic)},
+			Counter: &dto.Counter{Value: &value},
+		}},
+	}
+}
+
+// sumPoints returns the sum data points of a request, by topic.
+func sumPoints(t *testing.T, req *colmetricspb.ExportMetricsServiceRequest) map[string]*metricspb.NumberDataPoint {
+	t.Helper()
+	points := make(map[string]*metricspb.NumberDataPoint)
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				for _, p := range m.GetSum().GetDataPoints() {
+					for _, a := range p.GetAttributes() {
+						if a.GetKey() == "topic" {
+							points[a.GetValue().GetStringValue()] = p
+						}
+					}
+				}
+			}
+		}
+	}
+	return points
+}
+
+func TestPushStartTimes(t *testing.T) {
+	srv, requests := receiver(t)
+	var families []*dto.MetricFamily
+	e := &Exporter{
+		Endpoint:       srv.URL,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ResourceLabels: []string{"client_id"},
+		Gatherer:       prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return families, nil }),
+	}
+	if err := e.Start(); err != nil {
+		t.Fatal(err)
+	}
+	push := func(fs ...*dto.MetricFamily) (map[string]*metricspb.NumberDataPoint, uint64) {
+		t.Helper()
+		families = fs
+		// Distinct push times, whatever the clock resolution.
+		time.Sleep(time.Millisecond)
+		before := uint64(time.Now().UnixNano())
+		if err := e.Push(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return sumPoints(t, <-requests), before
+	}
+
+	points, first := push(counter("txmsgs", "c", "a", 5))
+	startA := points["a"].GetStartTimeUnixNano()
+	if startA < first || startA != points["a"].GetTimeUnixNano() {
+		t.Fatalf("new series: got start %d, time %d, push at %d", startA, points["a"].GetTimeUnixNano(), first)
+	}
+
+	points, second := push(counter("txmsgs", "c", "a", 7), counter("rxmsgs", "c", "b", 1))
+	if got := points["a"].GetStartTimeUnixNano(); got != startA {
+		t.Errorf("growing series: got start %d, want %d", got, startA)
+	}
+	if got := points["b"].GetStartTimeUnixNano(); got < second {
+		t.Errorf("series appearing later: got start %d, want at least %d", got, second)
+	}
+
+	// The counter of a is reset, b goes away.
+	points, third := push(counter("txmsgs", "c", "a", 2))
+	if got := points["a"].GetStartTimeUnixNano(); got < third {
+		t.Errorf("reset series: got start %d, want at least %d", got, third)
+	}
+	startA = points["a"].GetStartTimeUnixNano()
+
+	points, fourth := push(counter("txmsgs", "c", "a", 3), counter("rxmsgs", "c", "b", 4))
+	if got := points["a"].GetStartTimeUnixNano(); got != startA {
+		t.Errorf("series after reset: got start %d, want %d", got, startA)
+	}
+	if got := points["b"].GetStartTimeUnixNano(); got < fourth {
+		t.Errorf("series coming back: got start %d, want at least %d", got, fourth)
+	}
+}
@@ -0,0 +1,89 @@
+package prom
+
+import (
+	"sort"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// UpdateBatch replays a batch of stats, see UpdateClientBatch.
+func (p *PrometheusLibrdKafkaExporter) UpdateBatch(items []stats.BatchItem) []error {
+	return p.UpdateClientBatch(items, ClientInfo{})
+}
+
+// UpdateClientBatch replays a batch of stats, such as the stats buffered by a client while the
+// exporter was unreachable. The stats of every client are applied in the order they were emitted,
+// so the interval estimation and the restart detection see them as they were, see sortBatch.
+// It returns the error of every item, in the batch order.
+func (p *PrometheusLibrdKafkaExporter) UpdateClientBatch(items []stats.BatchItem, info ClientInfo) []error {
+	errs := make([]error, len(items))
+	order := make([]int, 0, len(items))
+	for i, item := range items {
+		if item.Err != nil {
+			errs[i] = item.Err
+			continue
+		}
+		order = append(order, i)
+	}
+	sortBatch(items, order)
+	for _, i := range order {
+		errs[i] = p.UpdateClientStats(items[i].Stats, info)
+	}
+	return errs
+}
+
+// sortBatch sorts the indexes of the stats of a batch by client, then in the order they were emitted.
+// The stats of different seconds are ordered by time. Within a second, ts (microseconds) orders the
+// stats of the same client instance, identified by the ts it was created at (ts - age): ts restarts
+// with the client, so the stats of every instance keep the batch order of the first of them. Stats
+// without ts keep the batch order.
+func sortBatch(items []stats.BatchItem, order []int) {
+	clients := make(map[int]string, len(order))
+	for _, i := range order {
+		clients[i] = strings.Join(getRootLabels(items[i].Stats), "/")
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := items[order[a]].Stats, items[order[b]].Stats
+		if ca, cb := clients[order[a]], clients[order[b]]; ca != cb {
+			return ca < cb
+		}
+		return sa.Time < sb.Time
+	})
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && clients[order[end]] == clients[order[start]] &&
+			items[order[end]].Stats.Time == items[order[start]].Stats.Time {
+			end++
+		}
+		sortSecond(items, order[start:end])
+		start = end
+	}
+}
+
+// sortSecond sorts the indexes of the stats of a client emitted within the same second, see sortBatch.
+func sortSecond(items []stats.BatchItem, order []int) {
+	// Instances are ranked by their first stats in the batch, every stats without ts being its own instance.
+	instances := make(map[int64]int)
+	rank := make(map[int]int, len(order))
+	next := 0
+	for _, i := range order {
+		s := items[i].Stats
+		if s.Ts == 0 {
+			rank[i], next = next, next+1
+			continue
+		}
+		created := s.Ts - s.Age
+		if _, ok := instances[created]; !ok {
+			instances[created], next = next, next+1
+		}
+		rank[i] = instances[created]
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ia, ib := order[a], order[b]
+		if rank[ia] != rank[ib] {
+			return rank[ia] < rank[ib]
+		}
+		return items[ia].Stats.Ts < items[ib].Stats.Ts
+	})
+}
@@ -0,0 +1,77 @@
+package prom
+
+import (
+	"errors"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// A client restarted while its stats were buffered: the ts of the new instance restarts
+// from 0, so the stats of different instances must not be ordered by ts.
+func TestUpdateBatchKeepsRestartOrder(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	at := func(ts, time int64) *stats.Stats {
+		s := loadFixture(t)
+		s.Ts, s.Time = ts, time
+		return s
+	}
+	first, second := at(5_000_000, 100), at(6_000_000, 101)
+	restarted := at(1_000, 101)
+	errInvalid := errors.New("invalid")
+	items := []stats.BatchItem{
+		{Stats: second},
+		{Err: errInvalid},
+		{Stats: first},
+		{Stats: restarted},
+	}
+	errs := exp.UpdateBatch(items)
+	for i, err := range errs {
+		if want := items[i].Err; err != want {
+			t.Errorf("item %d: got error %v, want %v", i, err, want)
+		}
+	}
+
+	families := gather(t, exp.Registry)
+	if v := value(find(families["librdkafka_exporter_client_restarts_total"], nil)); v != 1 {
+		t.Errorf("got %v restarts, want 1", v)
+	}
+	partition := map[string]string{"topic": "test", "partition": "0"}
+	txmsgs := value(find(families["librdkafka_topics_partitions_txmsgs"], partition))
+	if want := 2 * float64(first.Topics["test"].Partitions["0"].TxMsgs); txmsgs != want {
+		t.Errorf("txmsgs: got %v, want %v", txmsgs, want)
+	}
+}
+
+// Stats emitted within the same second are ordered by ts, per client instance.
+func TestUpdateBatchOrdersSecondByTs(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	// at returns the stats of the client instance created at ts 1s, emitted at ts.
+	at := func(name string, ts int64) *stats.Stats {
+		s := loadFixture(t)
+		s.Name, s.Ts, s.Age, s.Time = name, ts, ts-1_000_000, 100
+		return s
+	}
+	items := []stats.BatchItem{
+		{Stats: at("rdkafka#producer-1", 3_000_000)},
+		{Stats: at("rdkafka#producer-2", 2_500_000)},
+		{Stats: at("rdkafka#producer-1", 2_000_000)},
+		{Stats: at("rdkafka#producer-2", 1_500_000)},
+	}
+	for i, err := range exp.UpdateBatch(items) {
+		if err != nil {
+			t.Errorf("item %d: %v", i, err)
+		}
+	}
+
+	families := gather(t, exp.Registry)
+	for name, ts := range map[string]float64{"rdkafka#producer-1": 3_000_000, "rdkafka#producer-2": 2_500_000} {
+		client := map[string]string{"name": name}
+		if v := value(find(families["librdkafka_exporter_client_restarts_total"], client)); v != 0 {
+			t.Errorf("%s: got %v restarts, want 0", name, v)
+		}
+		if snap := exp.Snapshots["rdkafka/"+name+"/producer"]; snap == nil || snap.ts != ts {
+			t.Errorf("%s: last stats not applied last", name)
+		}
+	}
+}
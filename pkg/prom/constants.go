@@ -1,9 +1,12 @@
 package prom
 
+import "time"
+
 const (
 	GAUGE     = "gauge"
 	COUNTER   = "counter"
 	INFO      = "info"
+	STATE     = "state" // StateSet of a librdkafka state field
 	HISTOGRAM = "histrogram"
 	SUMMARY   = "summary"
 	WINDOW    = "windowStats" //librdkafka window stats
@@ -17,9 +20,27 @@ const (
 	TOPICS     = "topics_"
 	PARTITIONS = "partitions_"
 	BROKERS    = "brokers_"
+	EXPORTER   = "exporter_"
+
+	LAG_GROUP = "lag" // metric group of the consumer lag rollups
+
+	WINDOW_GAUGES  = "gauges" // a gauge per window field (_p99, _avg, ...)
+	WINDOW_SUMMARY = SUMMARY  // a summary with quantile label, _sum and _count
+	WINDOW_NATIVE  = "native" // a native histogram accumulated from every push
+
+	NATIVE_HISTOGRAM_SCHEMA = 3 // bucket growth factor 2^(2^-3) ~ 1.09
+
+	DEFAULT_STATS_INTERVAL   = 15 * time.Second
+	DEFAULT_GRACE_MULTIPLIER = 3
 )
 
 var ROOT_LABELS = []string{"client_id", "name", "type"}
 
+// INFO_LABELS are the labels of the client_info metric describing the client, after the ROOT_LABELS.
+var INFO_LABELS = []string{"user_agent", "remote_addr", "librdkafka_version", "language_version"}
+
+// RENDERED_LABELS are the labels added when rendering the window stats as summaries or histograms.
+var RENDERED_LABELS = []string{"quantile", "le"}
+
 var STRING_LABELS = []string{"client_id", "name", "type"}
 var FLOAT_LABELS = []string{"ts", "time"}
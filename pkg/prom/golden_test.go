@@ -0,0 +1,78 @@
+package prom
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden expositions in testdata")
+
+// volatile are the metrics depending on the time the stats are pushed, left out of the golden expositions.
+var volatile = map[string]bool{
+	"librdkafka_exporter_client_last_seen_timestamp_seconds": true,
+}
+
+// The metrics of the fixture match the golden expositions, regenerated with go test -update.
+func TestGoldenExposition(t *testing.T) {
+	for _, tc := range []struct {
+		golden      string
+		windowStats string
+	}{
+		{"stats.prom", WINDOW_GAUGES},
+		{"stats_summary.prom", WINDOW_SUMMARY},
+	} {
+		settings := DefaultSettings()
+		settings.WindowStats = tc.windowStats
+		exp := newTestExporter(t, settings)
+		// Two pushes, so that the summaries accumulate their count and sum.
+		for i := 0; i < 2; i++ {
+			if err := exp.UpdateStats(loadFixture(t)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		g := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+			mfs, err := exp.Registry.Gather()
+			stable := mfs[:0]
+			for _, mf := range mfs {
+				if !volatile[mf.GetName()] {
+					stable = append(stable, mf)
+				}
+			}
+			return stable, err
+		})
+
+		path := filepath.Join("testdata", tc.golden)
+		if *update {
+			mfs, err := g.Gather()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			for _, mf := range mfs {
+				if _, err := expfmt.MetricFamilyToText(&buf, mf); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		golden, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = testutil.GatherAndCompare(g, golden)
+		golden.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tc.golden, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package prom
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Group is a Pushgateway grouping key, the job and the other labels of a
+// /metrics/job/<job>/<label>/<value> push, keyed by label name.
+type Group map[string]string
+
+// grouping is a Group prepared for rendering: its key and its label pairs sorted by name.
+type grouping struct {
+	key    string
+	labels []*dto.LabelPair
+}
+
+func newGrouping(g Group) *grouping {
+	if len(g) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	labels := make([]*dto.LabelPair, 0, len(names))
+	for _, name := range names {
+		name, value := name, g[name]
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+		labels = append(labels, &dto.LabelPair{Name: &name, Value: &value})
+	}
+	return &grouping{key: strings.Join(pairs, ","), labels: labels}
+}
+
+// groupedMetric adds the labels of a group to a metric. Like with the Pushgateway, the grouping
+// labels replace the labels of the metric with the same name, which ValidateGroup rejects.
+type groupedMetric struct {
+	prometheus.Metric
+	group *grouping
+}
+
+func (m groupedMetric) Write(out *dto.Metric) error {
+	if err := m.Metric.Write(out); err != nil {
+		return err
+	}
+	labels := make([]*dto.LabelPair, 0, len(out.Label)+len(m.group.labels))
+	for _, l := range out.Label {
+		if !m.group.has(l.GetName()) {
+			labels = append(labels, l)
+		}
+	}
+	labels = append(labels, m.group.labels...)
+	sort.Slice(labels, func(i, j int) bool { return labels[i].GetName() < labels[j].GetName() })
+	out.Label = labels
+	return nil
+}
+
+func (g *grouping) has(name string) bool {
+	for _, l := range g.labels {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupError is the error of a grouping key having a label of the series of the exporter.
+type GroupError struct {
+	Label string
+}
+
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("grouping label %q is a label of the exported series", e.Label)
+}
+
+// ValidateGroup checks that the labels of a group are not labels of the series of the exporter,
+// which they would replace, making the series of different clients identical.
+func (p *PrometheusLibrdKafkaExporter) ValidateGroup(group Group) error {
+	p.MapMutex.RLock()
+	defer p.MapMutex.RUnlock()
+	names := make([]string, 0, len(group))
+	for name := range group {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p.seriesLabel(name) {
+			return &GroupError{Label: name}
+		}
+	}
+	return nil
+}
+
+// seriesLabel reports whether name is a label of the series of the exporter. MapMutex must be held.
+func (p *PrometheusLibrdKafkaExporter) seriesLabel(name string) bool {
+	if slices.Contains(ROOT_LABELS, name) || slices.Contains(INFO_LABELS, name) || slices.Contains(RENDERED_LABELS, name) {
+		return true
+	}
+	if _, ok := p.constLabels[name]; ok {
+		return true
+	}
+	for _, metrics := range []map[string]*MetricDesc{p.Metrics, p.Windows} {
+		for _, metric := range metrics {
+			if slices.Contains(metric.Labels, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpdateGroupStats replaces the snapshot of a client pushed to a group, the group labels being
+// added to all its series. With replace, the other clients of the group are removed (Pushgateway PUT).
+// The clients of a group don't expire, they are kept until the group is deleted. A group having
+// a label of the series is rejected with a GroupError.
+func (p *PrometheusLibrdKafkaExporter) UpdateGroupStats(group Group, s *stats.Stats, info ClientInfo, replace bool) error {
+	if err := p.ValidateGroup(group); err != nil {
+		return err
+	}
+	return p.update(s, info, newGrouping(group), replace)
+}
+
+// DeleteGroup removes all the clients pushed to a group with their series, returning how many were removed.
+func (p *PrometheusLibrdKafkaExporter) DeleteGroup(group Group) int {
+	g := newGrouping(group)
+	if g == nil {
+		return 0
+	}
+	p.MapMutex.Lock()
+	defer p.MapMutex.Unlock()
+	removed := 0
+	for key, snap := range p.Snapshots {
+		if snap.group != nil && snap.group.key == g.key {
+			delete(p.Snapshots, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Printf("Group {%s} deleted, removing the series of %d clients", g.key, removed)
+	}
+	return removed
+}
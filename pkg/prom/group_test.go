@@ -0,0 +1,43 @@
+package prom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestValidateGroup(t *testing.T) {
+	exp, err := NewPrometheusLibrdKafkaExporterWithOptions(Options{ConstLabels: prometheus.Labels{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		label string
+		valid bool
+	}{
+		{"instance", true},
+		{"client_id", false},
+		{"topic", false},
+		{"partition", false},
+		{"fetch_state", false},
+		{"remote_addr", false},
+		{"quantile", false},
+		{"env", false},
+	} {
+		err := exp.ValidateGroup(Group{"job": "app", tc.label: "x"})
+		var groupErr *GroupError
+		switch {
+		case tc.valid && err != nil:
+			t.Errorf("%s: %v", tc.label, err)
+		case !tc.valid && !errors.As(err, &groupErr):
+			t.Errorf("%s: got %v, want a GroupError", tc.label, err)
+		}
+	}
+	if err := exp.UpdateGroupStats(Group{"job": "app", "topic": "x"}, loadFixture(t), ClientInfo{}, true); err == nil {
+		t.Error("colliding group pushed")
+	}
+	if n := len(exp.Snapshots); n != 0 {
+		t.Errorf("got %d clients, want 0", n)
+	}
+}
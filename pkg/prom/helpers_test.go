@@ -0,0 +1,83 @@
+package prom
+
+import (
+	"os"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fixture is the stats payload of a producer shared with the cmd tool.
+const fixture = "../../cmd/stats.json"
+
+func loadFixture(t *testing.T) *stats.Stats {
+	t.Helper()
+	f, err := os.Open(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := stats.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestExporter(t *testing.T, settings Settings) *PrometheusLibrdKafkaExporter {
+	t.Helper()
+	exp, err := NewPrometheusLibrdKafkaExporterWithOptions(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp.Settings = settings
+	return exp
+}
+
+// gather returns the metric families of a registry by name.
+func gather(t *testing.T, g prometheus.Gatherer) map[string]*dto.MetricFamily {
+	t.Helper()
+	mfs, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	families := make(map[string]*dto.MetricFamily, len(mfs))
+	for _, mf := range mfs {
+		families[mf.GetName()] = mf
+	}
+	return families
+}
+
+// find returns the metric of a family having all the given labels.
+func find(mf *dto.MetricFamily, labels map[string]string) *dto.Metric {
+	if mf == nil {
+		return nil
+	}
+	for _, m := range mf.Metric {
+		matched := 0
+		for _, l := range m.Label {
+			if v, ok := labels[l.GetName()]; ok && v == l.GetValue() {
+				matched++
+			}
+		}
+		if matched == len(labels) {
+			return m
+		}
+	}
+	return nil
+}
+
+// value returns the value of a gauge, counter or untyped metric.
+func value(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	default:
+		return m.Untyped.GetValue()
+	}
+}
@@ -0,0 +1,155 @@
+package prom
+
+import (
+	"math"
+	"sort"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// windowTotals are the count and sum of the values of all the windows of a series. librdkafka
+// resets its windows every stats interval, their totals make the _count and _sum of a summary
+// monotonic, as those of a native histogram.
+type windowTotals struct {
+	count uint64
+	sum   float64
+}
+
+// nativeHistogram is a sparse (native) histogram accumulated from the
+// percentile snapshots librdkafka reports for every window.
+type nativeHistogram struct {
+	count     uint64
+	zeroCount uint64
+	sum       float64
+	buckets   map[int]uint64
+}
+
+func newNativeHistogram() *nativeHistogram {
+	return &nativeHistogram{buckets: make(map[int]uint64)}
+}
+
+func (h *nativeHistogram) clone() *nativeHistogram {
+	c := &nativeHistogram{count: h.count, zeroCount: h.zeroCount, sum: h.sum, buckets: make(map[int]uint64, len(h.buckets))}
+	for k, v := range h.buckets {
+		c.buckets[k] = v
+	}
+	return c
+}
+
+// bucketIndex returns the index of the native histogram bucket holding v,
+// bucket i covering (base^(i-1), base^i] with base 2^(2^-NATIVE_HISTOGRAM_SCHEMA).
+func bucketIndex(v float64) int {
+	return int(math.Ceil(math.Log2(v) * math.Exp2(NATIVE_HISTOGRAM_SCHEMA)))
+}
+
+// observeWindow adds the cnt values of a window, rebuilding an approximate
+// distribution from min, the percentiles and max. The values between two
+// consecutive percentiles are spread evenly over the buckets they cover.
+func (h *nativeHistogram) observeWindow(w *stats.Window) {
+	if w.Cnt <= 0 {
+		return
+	}
+	points := []struct {
+		q float64
+		v int64
+	}{
+		{0, w.Min}, {0.5, w.P50}, {0.75, w.P75}, {0.9, w.P90}, {0.95, w.P95},
+		{0.99, w.P99}, {0.9999, w.P99_99}, {1, w.Max},
+	}
+	cnt := float64(w.Cnt)
+	lo := float64(points[0].v)
+	var assigned uint64
+	for _, p := range points[1:] {
+		hi := math.Max(lo, float64(p.v))
+		n := uint64(math.Round(cnt*p.q)) - assigned
+		assigned += n
+		h.observeRange(lo, hi, n)
+		lo = hi
+	}
+	h.count += uint64(w.Cnt)
+	h.sum += float64(w.Sum)
+}
+
+func (h *nativeHistogram) observeRange(lo, hi float64, n uint64) {
+	if n == 0 {
+		return
+	}
+	if hi <= 0 {
+		h.zeroCount += n
+		return
+	}
+	first, last := bucketIndex(math.Max(lo, 1)), bucketIndex(hi)
+	if first > last {
+		first = last
+	}
+	k := uint64(last - first + 1)
+	for i := first; i <= last; i++ {
+		c := n / k
+		if uint64(i-first) < n%k {
+			c++
+		}
+		if c > 0 {
+			h.buckets[i] += c
+		}
+	}
+}
+
+// constNativeHistogram renders a nativeHistogram as a prometheus.Metric.
+type constNativeHistogram struct {
+	desc   *prometheus.Desc
+	hist   *nativeHistogram
+	labels []*dto.LabelPair
+}
+
+func newConstNativeHistogram(desc *prometheus.Desc, hist *nativeHistogram, labelValues ...string) prometheus.Metric {
+	return &constNativeHistogram{desc: desc, hist: hist, labels: prometheus.MakeLabelPairs(desc, labelValues)}
+}
+
+func (m *constNativeHistogram) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+func (m *constNativeHistogram) Write(out *dto.Metric) error {
+	h := m.hist
+	indexes := make([]int, 0, len(h.buckets))
+	for i := range h.buckets {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	var spans []*dto.BucketSpan
+	var deltas []int64
+	var prevIndex int
+	var prevCount int64
+	for n, i := range indexes {
+		gap := int32(i - prevIndex - 1)
+		if n == 0 {
+			gap = int32(i)
+		}
+		if n == 0 || gap != 0 {
+			spans = append(spans, &dto.BucketSpan{Offset: &gap, Length: new(uint32)})
+		}
+		*spans[len(spans)-1].Length++
+		count := int64(h.buckets[i])
+		deltas = append(deltas, count-prevCount)
+		prevIndex, prevCount = i, count
+	}
+
+	schema := int32(NATIVE_HISTOGRAM_SCHEMA)
+	zeroThreshold := 0.0
+	count, zeroCount, sum := h.count, h.zeroCount, h.sum
+	out.Label = m.labels
+	out.Histogram = &dto.Histogram{
+		SampleCount:   &count,
+		SampleSum:     &sum,
+		Schema:        &schema,
+		ZeroThreshold: &zeroThreshold,
+		ZeroCount:     &zeroCount,
+		PositiveSpan:  spans,
+		PositiveDelta: deltas,
+	}
+	return nil
+}
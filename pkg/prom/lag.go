@@ -0,0 +1,128 @@
+package prom
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// UNASSIGNED_PARTITION is the internal partition librdkafka keeps the messages of unknown partitions in (UA).
+const UNASSIGNED_PARTITION = -1
+
+// Names of the consumer lag rollups, without the metrics prefix.
+const (
+	topicLagName     = TOPICS + "consumer_lag"
+	topicLagMaxName  = TOPICS + "consumer_lag_max"
+	topicCatchUpName = TOPICS + "consumer_lag_catchup_seconds"
+	groupLagName     = CGRP + "consumer_lag"
+)
+
+// partitionOffsets are the offsets of a partition kept to estimate the consumption and production rates.
+type partitionOffsets struct {
+	committed float64
+	hi        float64
+}
+
+// buildLagMetrics builds the consumer lag rollups computed from the partition stats.
+func (exp *PrometheusLibrdKafkaExporter) buildLagMetrics() error {
+	for _, name := range []string{topicLagName, topicLagMaxName, topicCatchUpName, groupLagName} {
+		if _, ok := exp.Metrics[exp.Prefix+name]; ok {
+			return fmt.Errorf("metric %q is already defined by the exporter consumer lag rollups", name)
+		}
+	}
+	topicLabels := withLabels(ROOT_LABELS, "topic")
+	exp.BuildGauge(exp.Prefix+topicLagName, "Sum of the consumer lag of the partitions of the topic.", topicLabels)
+	exp.BuildGauge(exp.Prefix+topicLagMaxName, "Max consumer lag of the partitions of the topic.", topicLabels)
+	exp.BuildGauge(exp.Prefix+topicCatchUpName,
+		"Estimated time to consume the lag of the topic at the current consumption and production rates, +Inf when the lag grows.",
+		topicLabels)
+	exp.BuildGauge(exp.Prefix+groupLagName, "Sum of the consumer lag of the partitions assigned to the group member.",
+		withLabels(ROOT_LABELS, "group"))
+	return nil
+}
+
+// unassigned reports whether obj is the internal unassigned partition.
+func unassigned(obj interface{}) bool {
+	switch p := obj.(type) {
+	case stats.Partition:
+		return p.Partition == UNASSIGNED_PARTITION
+	case *stats.Partition:
+		return p.Partition == UNASSIGNED_PARTITION
+	}
+	return false
+}
+
+// updateLag adds the consumer lag rollups of a client, labeled with root, to the snapshot being built. Partitions with an
+// unknown lag (-1, as reported by producers) are ignored. The time to catch up is estimated from the
+// committed and high watermark offsets of the previous snapshot of the client.
+func (p *PrometheusLibrdKafkaExporter) updateLag(snap *snapshot, s *stats.Stats, root []string) {
+	if !p.ConsumerLag {
+		return
+	}
+	var dt float64
+	if snap.prev != nil && snap.prev.ts > 0 && snap.ts > snap.prev.ts {
+		dt = (snap.ts - snap.prev.ts) / 1e6
+	}
+	snap.offsets = make(map[string]partitionOffsets)
+
+	var groupLag float64
+	lagging := false
+	for _, topic := range stats.Children(s, "topics") {
+		t := topic.(stats.Topic)
+		var sum, max, rate float64
+		found := false
+		for _, child := range stats.Children(t, "partitions") {
+			part := child.(stats.Partition)
+			if part.Partition == UNASSIGNED_PARTITION || part.ConsumerLag < 0 {
+				continue
+			}
+			lag := float64(part.ConsumerLag)
+			sum += lag
+			max = math.Max(max, lag)
+			found = true
+
+			key := t.Topic + "/" + strconv.Itoa(int(part.Partition))
+			offsets := partitionOffsets{committed: float64(part.CommittedOffset), hi: float64(part.HiOffset)}
+			snap.offsets[key] = offsets
+			if prev, ok := snap.prev.partitionOffsets(key); ok && dt > 0 && offsets.committed >= 0 && prev.committed >= 0 {
+				rate += (offsets.committed - prev.committed) - (offsets.hi - prev.hi)
+			}
+		}
+		if !found {
+			continue
+		}
+		labels := withLabels(root, t.Topic)
+		p.updateMetric(snap, p.Prefix+topicLagName, sum, labels)
+		p.updateMetric(snap, p.Prefix+topicLagMaxName, max, labels)
+		if dt > 0 {
+			p.updateMetric(snap, p.Prefix+topicCatchUpName, catchUp(sum, rate/dt), labels)
+		}
+		groupLag += sum
+		lagging = true
+	}
+	if lagging && s.Cgrp != nil && s.Cgrp.State != "" {
+		p.updateMetric(snap, p.Prefix+groupLagName, groupLag, withLabels(root, snap.info.GroupID))
+	}
+}
+
+// partitionOffsets returns the offsets of a partition in the snapshot, if any.
+func (s *snapshot) partitionOffsets(key string) (partitionOffsets, bool) {
+	if s == nil {
+		return partitionOffsets{}, false
+	}
+	offsets, ok := s.offsets[key]
+	return offsets, ok
+}
+
+// catchUp returns the seconds needed to consume lag when the lag shrinks by rate messages per second.
+func catchUp(lag, rate float64) float64 {
+	switch {
+	case lag == 0:
+		return 0
+	case rate <= 0:
+		return math.Inf(1)
+	}
+	return lag / rate
+}
@@ -0,0 +1,134 @@
+package prom
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// consumerFixture returns the fixture as the stats of a consumer group member at ts, the partitions
+// of the topic being at the given committed and high watermark offsets.
+func consumerFixture(t *testing.T, ts int64, committed, hi map[string]int64) *stats.Stats {
+	t.Helper()
+	s := loadFixture(t)
+	s.Type, s.Ts = "consumer", ts
+	s.Cgrp = &stats.ConsumerGroup{State: "up", JoinState: "steady"}
+	topic := s.Topics["test"]
+	partition := topic.Partitions["0"]
+	topic.Partitions = map[string]stats.Partition{"-1": topic.Partitions["-1"]}
+	for name := range committed {
+		id, err := strconv.Atoi(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := partition
+		p.Partition = int32(id)
+		p.CommittedOffset, p.HiOffset = committed[name], hi[name]
+		p.ConsumerLag = hi[name] - committed[name]
+		topic.Partitions[name] = p
+	}
+	// A partition of a producer, with an unknown lag.
+	p := partition
+	p.Partition, p.ConsumerLag = int32(len(committed)), -1
+	topic.Partitions[strconv.Itoa(len(committed))] = p
+	s.Topics["test"] = topic
+	return s
+}
+
+func TestConsumerLag(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ConsumerLag = true
+	exp := newTestExporter(t, settings)
+	info := ClientInfo{GroupID: "app"}
+	topic := map[string]string{"client_id": "rdkafka", "type": "consumer", "topic": "test"}
+
+	s := consumerFixture(t, 1e6, map[string]int64{"0": 1000, "1": 500}, map[string]int64{"0": 1100, "1": 800})
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	for name, want := range map[string]float64{
+		"librdkafka_topics_consumer_lag":     400,
+		"librdkafka_topics_consumer_lag_max": 300,
+	} {
+		if m := find(families[name], topic); m == nil || value(m) != want {
+			t.Errorf("%s: got %v, want %v", name, m, want)
+		}
+	}
+	if m := find(families["librdkafka_consumergroups_consumer_lag"], map[string]string{"group": "app"}); m == nil || value(m) != 400 {
+		t.Errorf("group lag: got %v, want 400", m)
+	}
+	if _, ok := families["librdkafka_topics_consumer_lag_catchup_seconds"]; ok {
+		t.Error("catch-up time estimated from a single push")
+	}
+
+	// 10s later, 300 messages are consumed and 100 produced: the lag shrinks by 20 messages per second.
+	s = consumerFixture(t, 11e6, map[string]int64{"0": 1100, "1": 700}, map[string]int64{"0": 1150, "1": 850})
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	families = gather(t, exp.Registry)
+	if m := find(families["librdkafka_topics_consumer_lag"], topic); m == nil || value(m) != 200 {
+		t.Errorf("lag: got %v, want 200", m)
+	}
+	if m := find(families["librdkafka_topics_consumer_lag_catchup_seconds"], topic); m == nil || value(m) != 10 {
+		t.Errorf("catch-up: got %v, want 10", m)
+	}
+
+	// The lag grows: the consumer never catches up.
+	s = consumerFixture(t, 21e6, map[string]int64{"0": 1110, "1": 700}, map[string]int64{"0": 1400, "1": 1000})
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	if m := find(gather(t, exp.Registry)["librdkafka_topics_consumer_lag_catchup_seconds"], topic); m == nil || !math.IsInf(value(m), 1) {
+		t.Errorf("catch-up: got %v, want +Inf", m)
+	}
+
+	// Without a consumer group, only the topic rollups are exported.
+	exp = newTestExporter(t, settings)
+	s.Cgrp = nil
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	families = gather(t, exp.Registry)
+	if _, ok := families["librdkafka_consumergroups_consumer_lag"]; ok {
+		t.Error("group lag exported without a consumer group")
+	}
+	if find(families["librdkafka_topics_consumer_lag"], topic) == nil {
+		t.Error("topic lag not exported")
+	}
+}
+
+// A producer reports an unknown lag, no rollup is exported.
+func TestConsumerLagProducer(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ConsumerLag = true
+	exp := newTestExporter(t, settings)
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	for _, name := range []string{"librdkafka_topics_consumer_lag", "librdkafka_topics_consumer_lag_max", "librdkafka_consumergroups_consumer_lag"} {
+		if _, ok := families[name]; ok {
+			t.Errorf("%s exported for a producer", name)
+		}
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	for _, tc := range []struct {
+		lag, rate, want float64
+	}{
+		{0, 0, 0},
+		{0, -5, 0},
+		{100, 20, 5},
+		{100, 0, math.Inf(1)},
+		{100, -20, math.Inf(1)},
+	} {
+		if got := catchUp(tc.lag, tc.rate); got != tc.want {
+			t.Errorf("catchUp(%v, %v): got %v, want %v", tc.lag, tc.rate, got, tc.want)
+		}
+	}
+}
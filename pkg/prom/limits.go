@@ -0,0 +1,150 @@
+package prom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	LIMIT_SERIES_PER_CLIENT    = "series_per_client"
+	LIMIT_TOPICS_PER_CLIENT    = "topics_per_client"
+	LIMIT_PARTITIONS_PER_TOPIC = "partitions_per_topic"
+	LIMIT_SERIES               = "series"
+
+	TOPIC_LABEL     = "topic"
+	PARTITION_LABEL = "partition"
+)
+
+// Limits bound the series built from the stats, a zero limit is unlimited. The series a client
+// already has are always kept, only its new series are dropped once a limit is reached. The
+// limits are checked without blocking the other pushes, concurrent pushes can overshoot them.
+type Limits struct {
+	SeriesPerClient int
+	// TopicsPerClient and PartitionsPerTopic count the values of the topic and partition labels.
+	TopicsPerClient    int
+	PartitionsPerTopic int
+	// Series is the total of the series of all the clients.
+	Series int
+}
+
+// LimitError reports the series of a push dropped by the limits. The other series of
+// the push are kept, unless all were dropped and the push Rejected.
+type LimitError struct {
+	Client   string
+	Dropped  map[string]int // series dropped by limit
+	Rejected bool
+}
+
+func (e *LimitError) Error() string {
+	reasons := make([]string, 0, len(e.Dropped))
+	total := 0
+	for reason, n := range e.Dropped {
+		reasons = append(reasons, fmt.Sprintf("%s: %d", reason, n))
+		total += n
+	}
+	sort.Strings(reasons)
+	if e.Rejected {
+		return fmt.Sprintf("client %s rejected, its %d series exceed the limits (%s)", e.Client, total, strings.Join(reasons, ", "))
+	}
+	return fmt.Sprintf("client %s: %d new series dropped by the limits (%s)", e.Client, total, strings.Join(reasons, ", "))
+}
+
+// limitSamples drops the new series of a snapshot beyond the limits, returning how many were
+// dropped by limit, nil when none. MapMutex must be held.
+func (p *PrometheusLibrdKafkaExporter) limitSamples(snap *snapshot, key string) map[string]int {
+	limits := p.Limits
+	if limits == (Limits{}) {
+		return nil
+	}
+	existing := make(map[string]bool)
+	if snap.prev != nil {
+		for i := range snap.prev.samples {
+			existing[counterKey(&snap.prev.samples[i])] = true
+		}
+	}
+	others := 0
+	if limits.Series > 0 {
+		for other, o := range p.Snapshots {
+			if other != key {
+				others += len(o.samples)
+			}
+		}
+	}
+
+	topics := make(map[string]bool)
+	partitions := make(map[string]map[string]bool)
+	keep := make([]bool, len(snap.samples))
+	kept := 0
+	admit := func(i int) {
+		keep[i] = true
+		kept++
+		s := &snap.samples[i]
+		topic, ok := seriesLabel(s, TOPIC_LABEL)
+		if !ok {
+			return
+		}
+		topics[topic] = true
+		if partition, ok := seriesLabel(s, PARTITION_LABEL); ok {
+			if partitions[topic] == nil {
+				partitions[topic] = make(map[string]bool)
+			}
+			partitions[topic][partition] = true
+		}
+	}
+	for i := range snap.samples {
+		if existing[counterKey(&snap.samples[i])] {
+			admit(i)
+		}
+	}
+
+	var dropped map[string]int
+	for i := range snap.samples {
+		if keep[i] {
+			continue
+		}
+		s := &snap.samples[i]
+		topic, hasTopic := seriesLabel(s, TOPIC_LABEL)
+		partition, hasPartition := seriesLabel(s, PARTITION_LABEL)
+		var reason string
+		switch {
+		case limits.TopicsPerClient > 0 && hasTopic && !topics[topic] && len(topics) >= limits.TopicsPerClient:
+			reason = LIMIT_TOPICS_PER_CLIENT
+		case limits.PartitionsPerTopic > 0 && hasTopic && hasPartition && !partitions[topic][partition] &&
+			len(partitions[topic]) >= limits.PartitionsPerTopic:
+			reason = LIMIT_PARTITIONS_PER_TOPIC
+		case limits.SeriesPerClient > 0 && kept >= limits.SeriesPerClient:
+			reason = LIMIT_SERIES_PER_CLIENT
+		case limits.Series > 0 && others+kept >= limits.Series:
+			reason = LIMIT_SERIES
+		default:
+			admit(i)
+			continue
+		}
+		if dropped == nil {
+			dropped = make(map[string]int)
+		}
+		dropped[reason]++
+	}
+	if dropped == nil {
+		return nil
+	}
+	samples := snap.samples[:0]
+	for i, s := range snap.samples {
+		if keep[i] {
+			samples = append(samples, s)
+		}
+	}
+	snap.samples = samples
+	return dropped
+}
+
+// seriesLabel returns the value of a label of the series of a sample.
+func seriesLabel(s *sample, label string) (string, bool) {
+	for i, name := range s.metric.Labels {
+		if name == label {
+			return s.labels[i], true
+		}
+	}
+	return "", false
+}
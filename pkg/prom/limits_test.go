@@ -0,0 +1,178 @@
+package prom
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// wideFixture returns the fixture with the given number of topics, each with the given number of partitions.
+func wideFixture(t *testing.T, topics, partitions int) *stats.Stats {
+	t.Helper()
+	s := loadFixture(t)
+	topic := s.Topics["test"]
+	partition := topic.Partitions["0"]
+	s.Topics = make(map[string]stats.Topic, topics)
+	for i := 0; i < topics; i++ {
+		next := topic
+		next.Topic = fmt.Sprintf("topic-%d", i)
+		next.Partitions = make(map[string]stats.Partition, partitions)
+		for j := 0; j < partitions; j++ {
+			partition.Partition = int32(j)
+			next.Partitions[fmt.Sprint(j)] = partition
+		}
+		s.Topics[next.Topic] = next
+	}
+	return s
+}
+
+// series returns the number of series of a client and the partitions of each of its topics,
+// the topics being those of all the series, of the brokers as well.
+func series(t *testing.T, exp *PrometheusLibrdKafkaExporter, key string) (int, map[string]map[string]bool) {
+	t.Helper()
+	exp.MapMutex.RLock()
+	defer exp.MapMutex.RUnlock()
+	snap := exp.Snapshots[key]
+	if snap == nil {
+		t.Fatalf("client %s not found", key)
+	}
+	topics := make(map[string]map[string]bool)
+	for i := range snap.samples {
+		topic, ok := seriesLabel(&snap.samples[i], TOPIC_LABEL)
+		if !ok {
+			continue
+		}
+		if topics[topic] == nil {
+			topics[topic] = make(map[string]bool)
+		}
+		if partition, ok := seriesLabel(&snap.samples[i], PARTITION_LABEL); ok {
+			topics[topic][partition] = true
+		}
+	}
+	return len(snap.samples), topics
+}
+
+// limited returns the series dropped by the limits, failing unless err is a *LimitError rejecting the push or not.
+func limited(t *testing.T, err error, rejected bool) map[string]int {
+	t.Helper()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("got %v, want a limit error", err)
+	}
+	if limitErr.Rejected != rejected {
+		t.Fatalf("got rejected %t, want %t: %v", limitErr.Rejected, rejected, err)
+	}
+	return limitErr.Dropped
+}
+
+func TestLimitsPerTopic(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Limits = Limits{TopicsPerClient: 2, PartitionsPerTopic: 3}
+	exp := newTestExporter(t, settings)
+
+	dropped := limited(t, exp.UpdateStats(wideFixture(t, 4, 5)), false)
+	if dropped[LIMIT_TOPICS_PER_CLIENT] == 0 || dropped[LIMIT_PARTITIONS_PER_TOPIC] == 0 {
+		t.Errorf("got %v dropped, want both topic and partition limits", dropped)
+	}
+	_, topics := series(t, exp, fixtureKey)
+	if len(topics) != 2 {
+		t.Errorf("got %d topics, want 2", len(topics))
+	}
+	for topic, partitions := range topics {
+		if len(partitions) > 3 {
+			t.Errorf("%s: got %d partitions, want at most 3", topic, len(partitions))
+		}
+	}
+
+	// The topics and partitions kept stay the same from push to push.
+	limited(t, exp.UpdateStats(wideFixture(t, 4, 5)), false)
+	_, next := series(t, exp, fixtureKey)
+	for topic, partitions := range topics {
+		for partition := range partitions {
+			if !next[topic][partition] {
+				t.Errorf("%s/%s dropped by the next push", topic, partition)
+			}
+		}
+	}
+
+	reason := map[string]string{"client_id": "rdkafka", "reason": LIMIT_TOPICS_PER_CLIENT}
+	if m := find(gather(t, exp.Registry)["librdkafka_exporter_series_limited_total"], reason); m == nil || value(m) != float64(2*dropped[LIMIT_TOPICS_PER_CLIENT]) {
+		t.Errorf("got series limited %v, want %d", m, 2*dropped[LIMIT_TOPICS_PER_CLIENT])
+	}
+}
+
+func TestLimitsSeries(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	total, _ := series(t, exp, fixtureKey)
+
+	// The series of a client beyond its limit are dropped, the push is partially applied.
+	settings := DefaultSettings()
+	settings.Limits = Limits{SeriesPerClient: total / 2}
+	exp = newTestExporter(t, settings)
+	dropped := limited(t, exp.UpdateStats(loadFixture(t)), false)
+	if n, _ := series(t, exp, fixtureKey); n != total/2 || dropped[LIMIT_SERIES_PER_CLIENT] != total-total/2 {
+		t.Errorf("got %d series, %v dropped, want %d series", n, dropped, total/2)
+	}
+
+	// A client without any series left once the global limit is reached is rejected.
+	settings.Limits = Limits{Series: total}
+	exp = newTestExporter(t, settings)
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	other := loadFixture(t)
+	other.Name = "rdkafka#producer-2"
+	dropped = limited(t, exp.UpdateStats(other), true)
+	if dropped[LIMIT_SERIES] != total {
+		t.Errorf("got %v dropped, want %d series", dropped, total)
+	}
+	if _, ok := exp.Snapshots["rdkafka/rdkafka#producer-2/producer"]; ok {
+		t.Error("rejected client exported")
+	}
+	// The series of the first client are kept.
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Errorf("client within the limit: %v", err)
+	}
+}
+
+// The limits are reloaded: a lower limit keeps the series a client already has and a higher
+// one admits the series dropped so far.
+func TestLimitsReload(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	if err := exp.UpdateStats(wideFixture(t, 2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	total, before := series(t, exp, fixtureKey)
+
+	settings := DefaultSettings()
+	settings.Limits = Limits{SeriesPerClient: 10, TopicsPerClient: 1}
+	if err := exp.Reload(exp.Mappings, settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.UpdateStats(wideFixture(t, 2, 2)); err != nil {
+		t.Errorf("series already exported: %v", err)
+	}
+	if n, _ := series(t, exp, fixtureKey); n != total {
+		t.Errorf("got %d series, want %d", n, total)
+	}
+	dropped := limited(t, exp.UpdateStats(wideFixture(t, 3, 3)), false)
+	if n, topics := series(t, exp, fixtureKey); n != total || len(topics) != len(before) || dropped[LIMIT_TOPICS_PER_CLIENT] == 0 {
+		t.Errorf("got %d series and %d topics, %v dropped, want %d series and %d topics", n, len(topics), dropped, total, len(before))
+	}
+
+	settings.Limits = Limits{TopicsPerClient: len(before) + 1}
+	if err := exp.Reload(exp.Mappings, settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.UpdateStats(wideFixture(t, 3, 3)); err != nil {
+		t.Errorf("within the reloaded limits: %v", err)
+	}
+	if n, topics := series(t, exp, fixtureKey); n <= total || len(topics) != len(before)+1 {
+		t.Errorf("got %d series and %d topics, want more than %d series and %d topics", n, len(topics), total, len(before)+1)
+	}
+}
@@ -0,0 +1,333 @@
+package prom
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed mappings.yaml
+var defaultMappings []byte
+
+// Mappings maps the librdkafka stats to Prometheus metrics.
+type Mappings struct {
+	Metrics []Mapping `yaml:"metrics"`
+}
+
+// Mapping maps a field of a librdkafka stats object to Prometheus metrics.
+type Mapping struct {
+	Value   string         `yaml:"value"`
+	Type    string         `yaml:"type"`
+	Help    string         `yaml:"help"`
+	Name    string         `yaml:"name,omitempty"`
+	Labels  []MappingLabel `yaml:"labels,omitempty"`
+	Metrics []Mapping      `yaml:"metrics,omitempty"`
+	// KeyLabel is the label holding the key of a dict of numbers (req).
+	KeyLabel string `yaml:"key_label,omitempty"`
+	// States are the known values of a state field.
+	States []string `yaml:"states,omitempty"`
+}
+
+// MappingLabel is a label taken from a field of a stats object.
+type MappingLabel struct {
+	Name  string `yaml:"name"`
+	Field string `yaml:"field"`
+}
+
+// UnmarshalYAML accepts either a field name or a {name, field} object.
+func (l *MappingLabel) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var field string
+	if err := unmarshal(&field); err == nil {
+		l.Name, l.Field = field, field
+		return nil
+	}
+	type plain MappingLabel
+	if err := unmarshal((*plain)(l)); err != nil {
+		return err
+	}
+	if l.Field == "" {
+		l.Field = l.Name
+	}
+	return nil
+}
+
+// MetricName returns the name of the metric, or of the object prefix.
+func (m *Mapping) MetricName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Value
+}
+
+// LabelNames returns the names of the labels of an object.
+func (m *Mapping) LabelNames() []string {
+	names := make([]string, 0, len(m.Labels))
+	for _, l := range m.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// LabelFields returns the fields the labels of an object are taken from.
+func (m *Mapping) LabelFields() []string {
+	fields := make([]string, 0, len(m.Labels))
+	for _, l := range m.Labels {
+		fields = append(fields, l.Field)
+	}
+	return fields
+}
+
+// KeyLabels returns the label holding the key of a dict of numbers, if any.
+func (m *Mapping) KeyLabels() []string {
+	if m.KeyLabel == "" {
+		return nil
+	}
+	return []string{m.KeyLabel}
+}
+
+// DefaultMappings returns the mappings shipped with the exporter.
+func DefaultMappings() *Mappings {
+	mappings, err := ParseMappings(defaultMappings)
+	if err != nil {
+		panic(err)
+	}
+	return mappings
+}
+
+// LoadMappings reads and validates a YAML or JSON mappings file.
+func LoadMappings(path string) (*Mappings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := ParseMappings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return mappings, nil
+}
+
+// ParseMappings decodes and validates YAML or JSON mappings.
+func ParseMappings(data []byte) (*Mappings, error) {
+	var mappings Mappings
+	if err := yaml.UnmarshalStrict(data, &mappings); err != nil {
+		return nil, err
+	}
+	if err := mappings.Validate(); err != nil {
+		return nil, err
+	}
+	return &mappings, nil
+}
+
+// Validate checks the mappings against the librdkafka stats schema, reporting every problem found.
+func (m *Mappings) Validate() error {
+	return m.validate(PREFIX)
+}
+
+// validate checks the mappings for the metrics named with the given prefix.
+func (m *Mappings) validate(prefix string) error {
+	if len(m.Metrics) == 0 {
+		return errors.New("no metrics defined")
+	}
+	v := &mappingsValidator{names: make(map[string]string)}
+	v.validate(m.Metrics, &stats.Stats{}, "", prefix, ROOT_LABELS)
+	return errors.Join(v.errs...)
+}
+
+// Groups returns the groups of metrics of the mappings, named after their objects
+// (brokers, topics, partitions, ...), and LAG_GROUP for the consumer lag rollups.
+func (m *Mappings) Groups() []string {
+	groups := []string{LAG_GROUP}
+	var walk func(mappings []Mapping)
+	walk = func(mappings []Mapping) {
+		for _, mapping := range mappings {
+			if mapping.Type == OBJECT {
+				groups = append(groups, mapping.MetricName())
+				walk(mapping.Metrics)
+			}
+		}
+	}
+	walk(m.Metrics)
+	return groups
+}
+
+// Select returns the mappings without the objects of the groups not listed. The metrics of the
+// root stats object are always kept. An unknown group is an error.
+func (m *Mappings) Select(groups []string) (*Mappings, error) {
+	known := make(map[string]bool)
+	for _, group := range m.Groups() {
+		known[group] = true
+	}
+	enabled := make(map[string]bool)
+	var errs []error
+	for _, group := range groups {
+		if !known[group] {
+			errs = append(errs, fmt.Errorf("unknown metric group %q, expected one of %s", group, strings.Join(m.Groups(), ", ")))
+		}
+		enabled[group] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	var selectGroups func(mappings []Mapping) []Mapping
+	selectGroups = func(mappings []Mapping) []Mapping {
+		selected := make([]Mapping, 0, len(mappings))
+		for _, mapping := range mappings {
+			if mapping.Type == OBJECT {
+				if !enabled[mapping.MetricName()] {
+					continue
+				}
+				mapping.Metrics = selectGroups(mapping.Metrics)
+			}
+			selected = append(selected, mapping)
+		}
+		return selected
+	}
+	return &Mappings{Metrics: selectGroups(m.Metrics)}, nil
+}
+
+type mappingsValidator struct {
+	errs  []error
+	names map[string]string // metric name -> mapping path
+}
+
+func (v *mappingsValidator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// metric checks that a metric name is valid and defined only once.
+func (v *mappingsValidator) metric(path, name string) {
+	if !model.IsValidMetricName(model.LabelValue(name)) {
+		v.errorf(path, "invalid metric name %q", name)
+	}
+	if other, ok := v.names[name]; ok {
+		v.errorf(path, "metric %q already defined by %s", name, other)
+	}
+	v.names[name] = path
+}
+
+func (v *mappingsValidator) validate(mappings []Mapping, obj interface{}, parent, prefix string, labels []string) {
+	for i, m := range mappings {
+		path := fmt.Sprintf("%smetrics[%d]", parent, i)
+		if m.Value == "" {
+			v.errorf(path, "missing value")
+			continue
+		}
+		path = parent + m.Value
+		kind, child := stats.Field(obj, m.Value)
+		if m.Type != OBJECT && len(m.Metrics) > 0 {
+			v.errorf(path, "metrics are only allowed on objects")
+		}
+		if m.Type != OBJECT && m.Type != INFO && len(m.Labels) > 0 {
+			v.errorf(path, "labels are only allowed on objects and info metrics")
+		}
+		if m.KeyLabel != "" && m.Type != GAUGE && m.Type != COUNTER {
+			v.errorf(path, "key_label is only allowed on gauges and counters")
+		}
+		if len(m.States) > 0 && m.Type != STATE {
+			v.errorf(path, "states are only allowed on %s metrics", STATE)
+		}
+		if m.Type != OBJECT && m.Help == "" {
+			v.errorf(path, "missing help")
+		}
+		name := prefix + m.MetricName()
+		switch m.Type {
+		case GAUGE, COUNTER:
+			switch {
+			case kind == stats.KindMap && m.KeyLabel == "":
+				v.errorf(path, "%s field requires a key_label", kind)
+			case kind == stats.KindMap:
+				v.labels(path, labels, m.KeyLabel)
+			case m.KeyLabel != "":
+				v.errorf(path, "key_label on a %s field", kind)
+			// Fields unknown to the stats schema are allowed, newer librdkafka versions may report them.
+			case kind != stats.KindNumber && kind != stats.KindUnknown:
+				v.errorf(path, "%s field can't be a %s", kind, m.Type)
+			}
+			v.metric(path, name)
+		case INFO:
+			switch kind {
+			case stats.KindString:
+				if len(m.Labels) > 0 {
+					v.errorf(path, "labels on a %s field, the label is named after the value", kind)
+				}
+				v.labels(path, labels, m.Value)
+			case stats.KindObject, stats.KindObjects:
+				if len(m.Labels) == 0 {
+					v.errorf(path, "info of a %s without labels", kind)
+				}
+				v.objectLabels(path, labels, m.Labels, child)
+			default:
+				v.errorf(path, "%s field can't be an %s", kind, m.Type)
+			}
+			v.metric(path, name)
+		case STATE:
+			if kind != stats.KindString {
+				v.errorf(path, "%s field can't be a %s", kind, m.Type)
+			}
+			if len(m.States) == 0 {
+				v.errorf(path, "%s without states", m.Type)
+			}
+			v.labels(path, labels, m.Value)
+			v.metric(path, name)
+		case WINDOW:
+			if kind != stats.KindWindow {
+				v.errorf(path, "%s field can't be a %s", kind, m.Type)
+			}
+			v.metric(path, name)
+			for k := range getWindowsStats() {
+				v.metric(path, name+"_"+k)
+			}
+		case OBJECT:
+			if kind != stats.KindObject && kind != stats.KindObjects {
+				v.errorf(path, "%s field can't be an %s", kind, m.Type)
+				continue
+			}
+			if len(m.Metrics) == 0 {
+				v.errorf(path, "object without metrics")
+			}
+			objectLabels := v.objectLabels(path, labels, m.Labels, child)
+			v.validate(m.Metrics, child, path+".", name+"_", objectLabels)
+		default:
+			v.errorf(path, "unknown type %q, expected one of %s, %s, %s, %s, %s or %s", m.Type, GAUGE, COUNTER, WINDOW, INFO, STATE, OBJECT)
+		}
+	}
+}
+
+// labels checks that the names added to labels are valid and not duplicated, returning all the labels.
+func (v *mappingsValidator) labels(path string, labels []string, names ...string) []string {
+	all := withLabels(labels)
+	for _, name := range names {
+		if !model.LabelName(name).IsValid() {
+			v.errorf(path, "invalid label name %q", name)
+		}
+		for _, other := range all {
+			if other == name {
+				v.errorf(path, "duplicated label %q", name)
+			}
+		}
+		all = append(all, name)
+	}
+	return all
+}
+
+// objectLabels checks the labels taken from the fields of an object, returning all the labels.
+func (v *mappingsValidator) objectLabels(path string, labels []string, objectLabels []MappingLabel, obj interface{}) []string {
+	for _, l := range objectLabels {
+		if k, _ := stats.Field(obj, l.Field); k != stats.KindNumber && k != stats.KindString {
+			v.errorf(path, "label %q: %s field %q can't be a label", l.Name, k, l.Field)
+		}
+	}
+	names := make([]string, 0, len(objectLabels))
+	for _, l := range objectLabels {
+		names = append(names, l.Name)
+	}
+	return v.labels(path, labels, names...)
+}
@@ -0,0 +1,137 @@
+package prom
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestDefaultMappingsValidate(t *testing.T) {
+	if err := DefaultMappings().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMappingsSelectUnknownGroup(t *testing.T) {
+	if _, err := DefaultMappings().Select([]string{"topics", "nope"}); err == nil {
+		t.Fatal("expected an error for an unknown group")
+	}
+}
+
+// Epochs and message ids can be -1 or go backwards, they must not be rebased as counters on restarts.
+func TestEpochsAndMessageIDsAreGauges(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	for _, name := range []string{
+		"librdkafka_topics_partitions_leader_epoch",
+		"librdkafka_topics_partitions_stored_leader_epoch",
+		"librdkafka_topics_partitions_committed_leader_epoch",
+		"librdkafka_topics_partitions_acked_msgid",
+	} {
+		metric, ok := exp.Metrics[name]
+		if !ok {
+			t.Fatalf("%s not built", name)
+		}
+		if metric.ValueType != prometheus.GaugeValue {
+			t.Errorf("%s: got value type %v, want gauge", name, metric.ValueType)
+		}
+	}
+
+	withEpoch := func(s *stats.Stats, epoch, msgID int64) {
+		topic := s.Topics["test"]
+		p := topic.Partitions["0"]
+		p.LeaderEpoch, p.AckedMsgID = epoch, msgID
+		topic.Partitions["0"] = p
+	}
+	first := loadFixture(t)
+	withEpoch(first, 5, 1000)
+	if err := exp.UpdateStats(first); err != nil {
+		t.Fatal(err)
+	}
+	restarted := loadFixture(t)
+	restarted.Ts = first.Ts / 2
+	withEpoch(restarted, -1, 10)
+	if err := exp.UpdateStats(restarted); err != nil {
+		t.Fatal(err)
+	}
+
+	families := gather(t, exp.Registry)
+	partition := map[string]string{"topic": "test", "partition": "0"}
+	if v := value(find(families["librdkafka_exporter_client_restarts_total"], nil)); v != 1 {
+		t.Fatalf("got %v restarts, want 1", v)
+	}
+	if v := value(find(families["librdkafka_topics_partitions_leader_epoch"], partition)); v != -1 {
+		t.Errorf("leader_epoch: got %v, want -1", v)
+	}
+	if v := value(find(families["librdkafka_topics_partitions_acked_msgid"], partition)); v != 10 {
+		t.Errorf("acked_msgid: got %v, want 10", v)
+	}
+	// Counters are still rebased.
+	txmsgs := value(find(families["librdkafka_topics_partitions_txmsgs"], partition))
+	if want := 2 * float64(first.Topics["test"].Partitions["0"].TxMsgs); txmsgs != want {
+		t.Errorf("txmsgs: got %v, want %v", txmsgs, want)
+	}
+}
+
+// Every field of the stats schema is mapped by the default mappings, the fields identifying the
+// objects being their labels.
+func TestDefaultMappingsCoverSchema(t *testing.T) {
+	var walk func(typ reflect.Type, mappings []Mapping, labels []string, path string)
+	walk = func(typ reflect.Type, mappings []Mapping, labels []string, path string) {
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" || slices.Contains(labels, name) {
+				continue
+			}
+			m := slices.IndexFunc(mappings, func(m Mapping) bool { return m.Value == name })
+			if m < 0 {
+				t.Errorf("%s%s not mapped", path, name)
+				continue
+			}
+			if mappings[m].Type == OBJECT {
+				child := typ.Field(i).Type
+				if child.Kind() == reflect.Map {
+					child = child.Elem()
+				}
+				walk(child, mappings[m].Metrics, mappings[m].LabelFields(), path+name+".")
+			}
+		}
+	}
+	// The client is labeled with the root labels and its ts and time exported by the client_stats_timestamp_seconds metric.
+	walk(reflect.TypeOf(stats.Stats{}), DefaultMappings().Metrics, append(slices.Clone(ROOT_LABELS), FLOAT_LABELS...), "")
+}
+
+// The request counters of a broker are labeled with the request name and its partitions exported as info.
+func TestBrokerRequestsAndToppars(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	s := loadFixture(t)
+	b := s.Brokers["localhost:9092/2"]
+	b.Req = map[string]int64{"Produce": 10, "Metadata": 2}
+	s.Brokers["localhost:9092/2"] = b
+	if err := exp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	for request, want := range b.Req {
+		m := find(families["librdkafka_brokers_requests_total"], map[string]string{"broker": "localhost:9092/2", "request": request})
+		if m == nil || m.Counter == nil || value(m) != float64(want) {
+			t.Errorf("%s requests: got %v, want counter %d", request, m, want)
+		}
+	}
+	for broker, partition := range map[string]string{"localhost:9092/2": "1", "localhost:9093/3": "0"} {
+		m := find(families["librdkafka_brokers_toppars_info"], map[string]string{"broker": broker, "topic": "test", "partition": partition})
+		if m == nil || value(m) != 1 {
+			t.Errorf("%s: got toppar %v, want test/%s", broker, m, partition)
+		}
+	}
+	if m := find(families["librdkafka_brokers_toppars_info"], map[string]string{"broker": "localhost:9094/4"}); m != nil {
+		t.Errorf("broker without partitions: got toppar %v", m)
+	}
+}
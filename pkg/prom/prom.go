@@ -1,243 +1,593 @@
 package prom
 
 import (
-	"strconv"
+	"fmt"
+	"log"
+	"strings"
 	"sync"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// MetricDesc is a metric definition built from the mappings. Values are
+// rendered from it at scrape time as constant metrics.
+type MetricDesc struct {
+	Name      string
+	Desc      *prometheus.Desc
+	ValueType prometheus.ValueType
+	// Labels are the label names of the metric, in the order of the sample label values.
+	Labels []string
+}
+
+// sample is a single value decoded from a librdkafka stats payload.
+// Window stats rendered as summaries or native histograms carry the window
+// and its accumulated totals, or the accumulated histogram, instead of a value.
+type sample struct {
+	metric    *MetricDesc
+	value     float64
+	labels    []string
+	window    *stats.Window
+	totals    *windowTotals
+	histogram *nativeHistogram
+}
+
+// snapshot holds the samples decoded from the last stats payload pushed by a client.
+type snapshot struct {
+	samples  []sample
+	labels   []string
+	info     ClientInfo
+	ts       float64 // librdkafka monotonic clock (microseconds)
+	time     float64 // librdkafka wall clock (seconds)
+	age      float64 // age of the librdkafka client instance (microseconds)
+	restarts float64 // client instance restarts seen by the exporter
+	// counterBase holds, per counter series, the totals reported by the previous instances of the client.
+	counterBase map[string]float64
+	// counterLast holds the last total exported for every counter series of the client, present or not.
+	counterLast map[string]float64
+	lastSeen    time.Time     // last push received from the client
+	interval    time.Duration // estimated statistics.interval.ms of the client
+	// histograms accumulates the window stats of every series in WINDOW_NATIVE mode.
+	histograms map[string]*nativeHistogram
+	// totals accumulates the count and sum of the window stats of every series in WINDOW_SUMMARY mode.
+	totals map[string]*windowTotals
+	// offsets holds the offsets of the consumed partitions, keyed by topic/partition.
+	offsets map[string]partitionOffsets
+	group   *grouping // Pushgateway group the client was pushed to, if any
+	// generation of the exporter metrics the snapshot was built for.
+	generation int
+	prev       *snapshot // previous snapshot of the client, only set while building
+}
+
+// histogram returns the native histogram of a series, continuing the one of the previous snapshot.
+func (s *snapshot) histogram(key string, labels []string) *nativeHistogram {
+	key = key + "/" + strings.Join(labels, "/")
+	if s.histograms == nil {
+		s.histograms = make(map[string]*nativeHistogram)
+	}
+	hist := newNativeHistogram()
+	if s.prev != nil {
+		if prev, ok := s.prev.histograms[key]; ok {
+			hist = prev.clone()
+		}
+	}
+	s.histograms[key] = hist
+	return hist
+}
+
+// windowTotals returns the count and sum of the windows of a series, continuing those of the previous snapshot.
+func (s *snapshot) windowTotals(key string, labels []string, w *stats.Window) *windowTotals {
+	key = key + "/" + strings.Join(labels, "/")
+	if s.totals == nil {
+		s.totals = make(map[string]*windowTotals)
+	}
+	totals := &windowTotals{}
+	if s.prev != nil {
+		if prev, ok := s.prev.totals[key]; ok {
+			*totals = *prev
+		}
+	}
+	if w.Cnt > 0 {
+		totals.count += uint64(w.Cnt)
+		totals.sum += float64(w.Sum)
+	}
+	s.totals[key] = totals
+	return totals
+}
+
+// expired reports whether the client stopped pushing stats for longer than grace intervals.
+// Clients pushed to a group never expire.
+func (s *snapshot) expired(now time.Time, grace float64) bool {
+	return s.group == nil && now.Sub(s.lastSeen) > time.Duration(grace*float64(s.interval))
+}
+
+// ClientInfo describes the client pushing stats, as known by the transport.
+type ClientInfo struct {
+	UserAgent         string
+	RemoteAddr        string
+	LibrdkafkaVersion string
+	LanguageVersion   string
+	GroupID           string // group.id of a consumer, when supplied
+}
+
+// Settings are the settings of an exporter that can be changed with Reload while it runs.
+type Settings struct {
+	// StatsInterval is the statistics.interval.ms assumed for a client until
+	// its interval can be estimated from two consecutive pushes.
+	StatsInterval time.Duration
+	// GraceMultiplier is the number of missed intervals after which a client and all its series are dropped.
+	GraceMultiplier float64
+	// WindowStats selects how window stats are exported: WINDOW_GAUGES, WINDOW_SUMMARY or WINDOW_NATIVE.
+	WindowStats string
+	// UnassignedPartitions exports the internal unassigned partition (-1) of every topic.
+	UnassignedPartitions bool
+	// ConsumerLag exports the consumer lag rollups computed from the partition stats.
+	ConsumerLag bool
+	// Relabel rewrites and drops the series of the clients, none when nil.
+	Relabel *Relabeler
+	// Limits bound the series of the clients.
+	Limits Limits
+}
+
+// DefaultSettings returns the settings of a new exporter.
+func DefaultSettings() Settings {
+	return Settings{
+		StatsInterval:   DEFAULT_STATS_INTERVAL,
+		GraceMultiplier: DEFAULT_GRACE_MULTIPLIER,
+		WindowStats:     WINDOW_GAUGES,
+		ConsumerLag:     true,
+	}
+}
+
+// PrometheusLibrdKafkaExporter is a prometheus.Collector that keeps the last
+// librdkafka stats snapshot per client instance and renders it on every scrape.
 type PrometheusLibrdKafkaExporter struct {
-	MetricsValues map[string]float64
-	Metrics       map[string]interface{}
-	Registry      *prometheus.Registry
-	Prefix        string
-	MapMutex      sync.RWMutex
+	Mappings  *Mappings
+	Metrics   map[string]*MetricDesc
+	Windows   map[string]*MetricDesc
+	Snapshots map[string]*snapshot
+	Registry  *prometheus.Registry
+	// Registerer registers collectors in Registry, adding the exporter ConstLabels to their series.
+	Registerer prometheus.Registerer
+	Prefix     string
+	// constLabels are the ConstLabels added to every series by the Registerer.
+	constLabels prometheus.Labels
+	MapMutex    sync.RWMutex
+	Settings
+	// generation is incremented by every reload of the metrics, snapshots built
+	// for a previous generation may hold samples of removed metrics.
+	generation   int
+	reloadMutex  sync.Mutex
+	lastSeenDesc *prometheus.Desc
+	restartsDesc *prometheus.Desc
+	infoDesc     *prometheus.Desc
+	timeDesc     *prometheus.Desc
+	// ingestBytes and ingestDecodedBytes count the payloads received per content encoding.
+	ingestBytes        *prometheus.CounterVec
+	ingestDecodedBytes *prometheus.CounterVec
+	// seriesLimited counts the series dropped by the Limits per client.
+	seriesLimited *prometheus.CounterVec
+	// relabelCollisions counts the series dropped per client as relabeling made them identical to another.
+	relabelCollisions *prometheus.CounterVec
 }
 
+// Options are the settings of an exporter that can't change once it is built.
+type Options struct {
+	// Prefix of the metric names, PREFIX when empty.
+	Prefix string
+	// ConstLabels are added to every series of the exporter.
+	ConstLabels prometheus.Labels
+	// Mappings of the stats to metrics, the default mappings when nil.
+	Mappings *Mappings
+}
+
+// NewPrometheusLibrdKafkaExporter builds an exporter for the default mappings.
 func NewPrometheusLibrdKafkaExporter() *PrometheusLibrdKafkaExporter {
+	exporter, err := NewPrometheusLibrdKafkaExporterWithMappings(DefaultMappings())
+	if err != nil {
+		panic(err)
+	}
+	return exporter
+}
 
-	defaultRegistry := prometheus.NewRegistry()
-	prometheus.DefaultRegisterer = defaultRegistry
-	prometheus.DefaultGatherer = defaultRegistry
+// NewPrometheusLibrdKafkaExporterWithMappings builds an exporter for the given mappings.
+func NewPrometheusLibrdKafkaExporterWithMappings(mappings *Mappings) (*PrometheusLibrdKafkaExporter, error) {
+	return NewPrometheusLibrdKafkaExporterWithOptions(Options{Mappings: mappings})
+}
 
-	metricsMap := getMappings()
+// NewPrometheusLibrdKafkaExporterWithOptions builds an exporter with the given options. Every
+// exporter registers its metrics in its own Registry, so several exporters can run side by side.
+func NewPrometheusLibrdKafkaExporterWithOptions(opts Options) (*PrometheusLibrdKafkaExporter, error) {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = PREFIX
+	}
+	mappings := opts.Mappings
+	if mappings == nil {
+		mappings = DefaultMappings()
+	}
 
+	registry := prometheus.NewRegistry()
 	exporter := &PrometheusLibrdKafkaExporter{
-		Registry:      defaultRegistry,
-		Prefix:        PREFIX,
-		MetricsValues: make(map[string]float64),
-		Metrics:       make(map[string]interface{}),
-	}
-	// Build Root metrics
-	exporter.BuildMetrics(metricsMap, ROOT_LABELS, PREFIX)
-
-	//Build Brokers metrics
-	brokersMap := getBrokerMappings()
-	brokersLabels := append(ROOT_LABELS, "broker", "nodeid", "nodename", "source", "state")
-	exporter.BuildMetrics(brokersMap, brokersLabels, PREFIX+BROKERS)
-
-	// Build Topic Metrics
-	topicLabels := append(ROOT_LABELS, "topic")
-	topicMetricsMap := getTopicsMappings()
-	exporter.BuildMetrics(topicMetricsMap, topicLabels, PREFIX+TOPICS)
-
-	// Build ConsumerGroup Metrics
-	consumerGroupLabels := append(ROOT_LABELS, "state", "join_state", "rebalance_reason")
-	consumerGroupMetricsMap := getConsumerGroupMappings()
-	exporter.BuildMetrics(consumerGroupMetricsMap, consumerGroupLabels, PREFIX+CGRP)
-
-	//Build EOS
-	eosLabels := append(ROOT_LABELS, "idemp_state", "txn_state")
-	eosMetricsMap := getEOSMappings()
-	exporter.BuildMetrics(eosMetricsMap, eosLabels, PREFIX+EOS)
+		Registry:    registry,
+		Registerer:  prometheus.WrapRegistererWith(opts.ConstLabels, registry),
+		Prefix:      prefix,
+		constLabels: opts.ConstLabels,
+		Metrics:     make(map[string]*MetricDesc),
+		Windows:     make(map[string]*MetricDesc),
+		Snapshots:   make(map[string]*snapshot),
+		Settings:    DefaultSettings(),
+		lastSeenDesc: prometheus.NewDesc(prefix+EXPORTER+"client_last_seen_timestamp_seconds",
+			"Unix time of the last stats received from the client.", ROOT_LABELS, nil),
+		restartsDesc: prometheus.NewDesc(prefix+EXPORTER+"client_restarts_total",
+			"Number of restarts of the client detected from its ts and age stats.", ROOT_LABELS, nil),
+		infoDesc: prometheus.NewDesc(prefix+"client_info",
+			"Information about the client pushing stats.",
+			withLabels(ROOT_LABELS, INFO_LABELS...), nil),
+		timeDesc: prometheus.NewDesc(prefix+"client_stats_timestamp_seconds",
+			"Wall clock time of the client when the last stats were emitted.", ROOT_LABELS, nil),
+		ingestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + EXPORTER + "ingest_bytes_total",
+			Help: "Bytes of stats payloads received, as sent on the wire.",
+		}, []string{"encoding"}),
+		ingestDecodedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + EXPORTER + "ingest_decoded_bytes_total",
+			Help: "Bytes of stats payloads received, once decompressed.",
+		}, []string{"encoding"}),
+		seriesLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + EXPORTER + "series_limited_total",
+			Help: "Series of the stats dropped by the cardinality limits, counted on every push.",
+		}, []string{"client_id", "reason"}),
+		relabelCollisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + EXPORTER + "relabel_collisions_total",
+			Help: "Series of the stats dropped because relabeling made them identical to another series of the client, counted on every push.",
+		}, []string{"client_id"}),
+	}
+	if err := exporter.BuildMetrics(mappings); err != nil {
+		return nil, err
+	}
 
-	return exporter
+	for _, c := range []prometheus.Collector{exporter, exporter.ingestBytes, exporter.ingestDecodedBytes, exporter.seriesLimited, exporter.relabelCollisions} {
+		if err := exporter.Registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return exporter, nil
 }
 
-func (exp *PrometheusLibrdKafkaExporter) BuildMetrics(metricsMap []map[string]interface{}, labels []string, prefix string) {
-	for _, metric := range metricsMap {
-		mtype := metric[TYPE]
-		switch mtype {
+// BuildMetrics validates the mappings and builds their metrics.
+func (exp *PrometheusLibrdKafkaExporter) BuildMetrics(mappings *Mappings) error {
+	if err := mappings.validate(exp.Prefix); err != nil {
+		return fmt.Errorf("invalid mappings: %w", err)
+	}
+	exp.Mappings = mappings
+	exp.buildMetrics(mappings.Metrics, ROOT_LABELS, exp.Prefix)
+	return exp.buildLagMetrics()
+}
+
+func (exp *PrometheusLibrdKafkaExporter) buildMetrics(mappings []Mapping, labels []string, prefix string) {
+	for _, metric := range mappings {
+		name := prefix + metric.MetricName()
+		switch metric.Type {
 		case GAUGE: // Gauge
-			exp.BuildGauge(prefix+metric[VALUE].(string), metric[HELP].(string), labels)
+			exp.BuildGauge(name, metric.Help, withLabels(labels, metric.KeyLabels()...))
 		case COUNTER:
-			exp.BuildCounter(prefix+metric[VALUE].(string), metric[HELP].(string), labels)
+			exp.BuildCounter(name, metric.Help, withLabels(labels, metric.KeyLabels()...))
+		case STATE:
+			exp.BuildGauge(name, metric.Help, withLabels(labels, metric.Value))
+		case INFO:
+			infoLabels := metric.LabelNames()
+			if len(infoLabels) == 0 {
+				infoLabels = []string{metric.Value}
+			}
+			exp.BuildGauge(name, metric.Help, withLabels(labels, infoLabels...))
 		case WINDOW:
-			exp.BuildWindowStats(prefix+metric[VALUE].(string), labels)
+			exp.BuildWindowStats(name, metric.Help, labels)
 		case OBJECT:
-			childMetrics := metric[METRICS].([]map[string]interface{})
-			objectLabels := metric[LABELS].([]string)
-			exp.BuildMetrics(childMetrics, append(labels, objectLabels...), prefix+metric[VALUE].(string)+"_")
+			exp.buildMetrics(metric.Metrics, withLabels(labels, metric.LabelNames()...), name+"_")
 		}
 	}
 }
 
 func (exp *PrometheusLibrdKafkaExporter) BuildGauge(name, help string, labels []string) {
-	pGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: name,
-		Help: help,
-	},
-		labels,
-	)
-	prometheus.MustRegister(pGauge)
-	exp.Metrics[name] = pGauge
+	exp.Metrics[name] = &MetricDesc{
+		Name:      name,
+		Desc:      prometheus.NewDesc(name, help, labels, nil),
+		ValueType: prometheus.GaugeValue,
+		Labels:    labels,
+	}
 }
 
 func (exp *PrometheusLibrdKafkaExporter) BuildCounter(name, help string, labels []string) {
-	pCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
-		Name: name,
-		Help: help,
-	},
-		labels,
-	)
-	prometheus.MustRegister(pCounter)
-	exp.Metrics[name] = pCounter
+	exp.Metrics[name] = &MetricDesc{
+		Name:      name,
+		Desc:      prometheus.NewDesc(name, help, labels, nil),
+		ValueType: prometheus.CounterValue,
+		Labels:    labels,
+	}
 }
 
-func (exp *PrometheusLibrdKafkaExporter) BuildWindowStats(name string, labels []string) {
+// BuildWindowStats builds every representation of a window stat, a gauge per
+// window field and a summary or histogram, so WindowStats can be switched at any time.
+func (exp *PrometheusLibrdKafkaExporter) BuildWindowStats(name, help string, labels []string) {
 	for k, v := range getWindowsStats() {
 		exp.BuildGauge(name+"_"+k, v, labels)
 	}
+	// The summary or histogram has no single value type, it is rendered from the window.
+	exp.Windows[name] = &MetricDesc{
+		Name:      name,
+		Desc:      prometheus.NewDesc(name, help, labels, nil),
+		ValueType: prometheus.UntypedValue,
+		Labels:    labels,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (p *PrometheusLibrdKafkaExporter) Describe(ch chan<- *prometheus.Desc) {
+	p.MapMutex.RLock()
+	defer p.MapMutex.RUnlock()
+	for _, metric := range p.Metrics {
+		ch <- metric.Desc
+	}
+	for _, window := range p.Windows {
+		ch <- window.Desc
+	}
+	ch <- p.lastSeenDesc
+	ch <- p.restartsDesc
+	ch <- p.infoDesc
+	ch <- p.timeDesc
+}
+
+// Collect implements prometheus.Collector, rendering the last snapshot of every client.
+// Clients that stopped pushing stats are dropped before rendering.
+func (p *PrometheusLibrdKafkaExporter) Collect(ch chan<- prometheus.Metric) {
+	p.MapMutex.Lock()
+	defer p.MapMutex.Unlock()
+	p.expireSnapshots(time.Now())
+	for _, snap := range p.Snapshots {
+		if snap.generation != p.generation {
+			p.pruneSamples(snap)
+		}
+		send := func(m prometheus.Metric) { ch <- m }
+		if snap.group != nil {
+			send = func(m prometheus.Metric) { ch <- groupedMetric{m, snap.group} }
+		}
+		for _, s := range snap.samples {
+			if s.histogram != nil {
+				send(newConstNativeHistogram(s.metric.Desc, s.histogram, s.labels...))
+				continue
+			}
+			if s.window != nil {
+				send(prometheus.MustNewConstSummary(s.metric.Desc, s.totals.count, s.totals.sum,
+					getWindowQuantiles(s.window), s.labels...))
+				continue
+			}
+			send(prometheus.MustNewConstMetric(s.metric.Desc, s.metric.ValueType, s.value, s.labels...))
+		}
+		send(prometheus.MustNewConstMetric(p.lastSeenDesc, prometheus.GaugeValue,
+			float64(snap.lastSeen.UnixNano())/1e9, snap.labels...))
+		send(prometheus.MustNewConstMetric(p.restartsDesc, prometheus.CounterValue, snap.restarts, snap.labels...))
+		send(prometheus.MustNewConstMetric(p.infoDesc, prometheus.GaugeValue, 1,
+			withLabels(snap.labels, snap.info.UserAgent, snap.info.RemoteAddr, snap.info.LibrdkafkaVersion, snap.info.LanguageVersion)...))
+		if snap.time > 0 {
+			send(prometheus.MustNewConstMetric(p.timeDesc, prometheus.GaugeValue, snap.time, snap.labels...))
+		}
+	}
 }
 
-func getStringLabels(labels []string, obj map[string]interface{}, fields []string) []string {
-	strLabels := labels
+// expireSnapshots removes the clients not seen for GraceMultiplier times their interval. MapMutex must be held.
+func (p *PrometheusLibrdKafkaExporter) expireSnapshots(now time.Time) {
+	for key, snap := range p.Snapshots {
+		if snap.expired(now, p.GraceMultiplier) {
+			log.Printf("Client %s stopped pushing stats, removing its series", key)
+			delete(p.Snapshots, key)
+		}
+	}
+}
+
+// withLabels returns a new slice with values appended to labels, leaving labels untouched.
+func withLabels(labels []string, values ...string) []string {
+	l := make([]string, 0, len(labels)+len(values))
+	l = append(l, labels...)
+	return append(l, values...)
+}
+
+func getStringLabels(labels []string, obj interface{}, fields []string) []string {
+	strLabels := withLabels(labels)
 	for _, f := range fields {
-		strLabels = append(strLabels, obj[f].(string))
+		strLabels = append(strLabels, stats.Label(obj, f))
 	}
 	return strLabels
 }
 
-func getBrokerLabels(labels []string, brokerObj map[string]interface{}) []string {
-	brokerLabels := append(labels, brokerObj["name"].(string))
-	brokerLabels = append(brokerLabels, strconv.FormatFloat(brokerObj["nodeid"].(float64), 'f', -1, 64))
-	brokerLabels = getStringLabels(brokerLabels, brokerObj, []string{"nodename", "source", "state"})
-	return brokerLabels
+// getRootLabels returns a value for every ROOT_LABELS entry, empty when missing from the stats.
+func getRootLabels(s *stats.Stats) []string {
+	return getStringLabels(nil, s, ROOT_LABELS)
 }
 
-func (p *PrometheusLibrdKafkaExporter) UpdateStats(stats map[string]interface{}) error {
-	var labels []string
+// UpdateStats replaces the snapshot of the client that pushed the stats.
+func (p *PrometheusLibrdKafkaExporter) UpdateStats(s *stats.Stats) error {
+	return p.UpdateClientStats(s, ClientInfo{})
+}
 
-	for _, label := range ROOT_LABELS {
-		if stats[label] != nil {
-			var labelValue string // Store Label & Vale
-			if _, ok := stats[label].(float64); ok {
-				labelValue = strconv.FormatFloat(stats[label].(float64), 'f', -1, 64) // Store Label & Vale
-			}
-			if _, ok := stats[label].(string); ok {
-				labelValue = stats[label].(string) // Store Label & Vale
-			}
-			labels = append(labels, labelValue)
-		}
+// UpdateClientStats replaces the snapshot of the client that pushed the stats, recording what the transport knows about it.
+func (p *PrometheusLibrdKafkaExporter) UpdateClientStats(s *stats.Stats, info ClientInfo) error {
+	return p.update(s, info, nil, false)
+}
+
+// update replaces the snapshot of a client, pushed to a group or not. With replace,
+// the other clients of the group are removed. A *LimitError reports the series dropped by the Limits.
+func (p *PrometheusLibrdKafkaExporter) update(s *stats.Stats, info ClientInfo, group *grouping, replace bool) error {
+	if s == nil {
+		return stats.ErrEmpty
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	// The read lock keeps the metrics and settings from being reloaded while the snapshot is built.
+	p.MapMutex.RLock()
+	// The client labels are relabeled first, as they identify the client.
+	root := getRootLabels(s)
+	labels, keep := p.Relabel.relabelClient(root)
+	if !keep {
+		p.MapMutex.RUnlock()
+		return nil
 	}
+	key := strings.Join(labels, "/")
+	if group != nil {
+		key += "{" + group.key + "}"
+	}
+	snap := &snapshot{labels: labels, info: info, lastSeen: time.Now(),
+		ts: float64(s.Ts), time: float64(s.Time), age: float64(s.Age), group: group}
+	snap.interval, snap.generation = p.StatsInterval, p.generation
+	snap.prev = p.Snapshots[key]
+	root = p.Relabel.seriesLabels(root, labels)
+	p.updateObject(snap, s, p.Mappings.Metrics, root, p.Prefix)
+	p.updateLag(snap, s, root)
+	collisions := p.Relabel.relabelSamples(snap)
+	dropped := p.limitSamples(snap, key)
+	p.MapMutex.RUnlock()
 
-	// Update ROOT metrics
-	for key, value := range stats {
-		if _, ok := value.(float64); ok {
-			p.UpdateMetric(PREFIX+key, value.(float64), labels)
+	if collisions > 0 {
+		p.relabelCollisions.WithLabelValues(snap.labels[0]).Add(float64(collisions))
+	}
+	var err error
+	if dropped != nil {
+		for reason, n := range dropped {
+			p.seriesLimited.WithLabelValues(snap.labels[0], reason).Add(float64(n))
+		}
+		limited := &LimitError{Client: key, Dropped: dropped, Rejected: len(snap.samples) == 0}
+		if limited.Rejected {
+			return limited
 		}
+		err = limited
 	}
-	// Update Broker Metrics
-	if brokers, ok := stats["brokers"]; ok {
-		for _, broker := range brokers.(map[string]interface{}) {
-			brokerObj := broker.(map[string]interface{})
-			brokerLabels := getBrokerLabels(labels, brokerObj)
-			for key, value := range brokerObj {
-				if _, ok := value.(float64); ok {
-					p.UpdateMetric(PREFIX+BROKERS+key, value.(float64), brokerLabels)
-				} else {
-					if _, ok := value.(map[string]interface{}); ok {
-						for k := range getWindowsStats() {
-							v := value.(map[string]interface{})[k]
-							if _, ok := value.(float64); ok {
-								p.UpdateMetric(PREFIX+BROKERS+key+"_"+k, v.(float64), brokerLabels)
-							}
-						}
-					}
-				}
+
+	p.MapMutex.Lock()
+	prev := p.Snapshots[key]
+	if prev != nil {
+		snap.interval = estimateInterval(prev, snap)
+	}
+	snap.rebaseCounters(prev)
+	snap.prev = nil
+	if replace {
+		for other, o := range p.Snapshots {
+			if o.group != nil && o.group.key == group.key && other != key {
+				delete(p.Snapshots, other)
 			}
 		}
 	}
-	// Update Topic Metrics
-	if topics, ok := stats["topics"]; ok {
-		for _, topic := range topics.(map[string]interface{}) {
-			topicObj := topic.(map[string]interface{})
-			topicLabels := getStringLabels(labels, topicObj, []string{"topic"})
-			for key, value := range topicObj {
-				if _, ok := value.(float64); ok {
-					p.UpdateMetric(PREFIX+TOPICS+key, value.(float64), topicLabels)
-				} else {
-					if _, ok := value.(map[string]interface{}); ok {
-						for k := range getWindowsStats() {
-							v := value.(map[string]interface{})[k]
-							if _, ok := value.(float64); ok {
-								p.UpdateMetric(PREFIX+TOPICS+key+"_"+k, v.(float64), topicLabels)
-							}
-						}
-					}
+	p.Snapshots[key] = snap
+	p.expireSnapshots(snap.lastSeen)
+	p.MapMutex.Unlock()
+	return err
+}
+
+// CountIngest records a stats payload received with the given content encoding.
+func (p *PrometheusLibrdKafkaExporter) CountIngest(encoding string, wireBytes, decodedBytes int64) {
+	p.ingestBytes.WithLabelValues(encoding).Add(float64(wireBytes))
+	p.ingestDecodedBytes.WithLabelValues(encoding).Add(float64(decodedBytes))
+}
+
+// estimateInterval derives the client statistics.interval.ms from two consecutive pushes,
+// preferring the librdkafka monotonic clock over the arrival times. A ts going back is a
+// restart, the previous interval is kept: the arrival times of a replayed batch are not the
+// times the stats were emitted.
+func estimateInterval(prev, next *snapshot) time.Duration {
+	interval := next.lastSeen.Sub(prev.lastSeen)
+	if next.ts > 0 && prev.ts > 0 {
+		interval = time.Duration(next.ts-prev.ts) * time.Microsecond
+	}
+	if interval <= 0 {
+		return prev.interval
+	}
+	return interval
+}
+
+// updateObject adds the metrics of a stats object to the snapshot being built, walking down its child objects.
+func (p *PrometheusLibrdKafkaExporter) updateObject(snap *snapshot, obj interface{}, mappings []Mapping, labels []string, prefix string) {
+	for _, m := range mappings {
+		name := prefix + m.MetricName()
+		switch m.Type {
+		case GAUGE, COUNTER:
+			if m.KeyLabel != "" {
+				for key, value := range stats.NumberMap(obj, m.Value) {
+					p.updateMetric(snap, name, value, withLabels(labels, key))
 				}
-				partitions := topicObj["partitions"].(map[string]interface{})
-				for _, partition := range partitions {
-					partitionObj := partition.(map[string]interface{})
-					partitionLabels := append(topicLabels, strconv.FormatFloat(partitionObj["partition"].(float64), 'f', -1, 64))
-					partitionLabels = append(partitionLabels, strconv.FormatFloat(partitionObj["broker"].(float64), 'f', -1, 64))
-					partitionLabels = append(partitionLabels, strconv.FormatFloat(partitionObj["leader"].(float64), 'f', -1, 64))
-					for key, value := range partitionObj {
-						if _, ok := value.(float64); ok {
-							p.UpdateMetric(PREFIX+TOPICS+PARTITIONS+key, value.(float64), partitionLabels)
-						}
-					}
+			} else if value, ok := stats.Number(obj, m.Value); ok {
+				p.updateMetric(snap, name, value, labels)
+			}
+		case STATE:
+			p.updateState(snap, name, stats.Label(obj, m.Value), m.States, labels)
+		case INFO:
+			if len(m.Labels) == 0 {
+				if value := stats.Label(obj, m.Value); value != "" {
+					p.updateMetric(snap, name, 1, withLabels(labels, value))
 				}
+				continue
 			}
-		}
-	}
-	// Update ConsumerGroup Metrics
-	if consumerGroup, ok := stats["cgrp"]; ok {
-		consumerGroupObj := consumerGroup.(map[string]interface{})
-		consumerGroupLabels := getStringLabels(labels, consumerGroupObj, []string{"state", "join_state", "rebalance_reason"})
-		for key, value := range consumerGroupObj {
-			if _, ok := value.(float64); ok {
-				p.UpdateMetric(PREFIX+CGRP+key, value.(float64), consumerGroupLabels)
+			for _, child := range stats.Children(obj, m.Value) {
+				p.updateMetric(snap, name, 1, getStringLabels(labels, child, m.LabelFields()))
+			}
+		case WINDOW:
+			if window := stats.WindowOf(obj, m.Value); window != nil {
+				p.updateWindow(snap, name, window, labels)
+			}
+		case OBJECT:
+			for _, child := range stats.Children(obj, m.Value) {
+				if !p.UnassignedPartitions && unassigned(child) {
+					continue
+				}
+				p.updateObject(snap, child, m.Metrics, getStringLabels(labels, child, m.LabelFields()), name+"_")
 			}
 		}
 	}
-	if eos, ok := stats["eos"]; ok {
-		eosObj := eos.(map[string]interface{})
-		eosObjLabels := getStringLabels(labels, eosObj, []string{"idemp_state", "txn_state"})
-		for key, value := range eosObj {
-			if _, ok := value.(float64); ok {
-				p.UpdateMetric(PREFIX+EOS+key, value.(float64), eosObjLabels)
-			}
+}
+
+// updateState adds a StateSet to the snapshot being built, a series per known state
+// set to 1 for the current state and 0 for the others. An unknown current state is added as well.
+func (p *PrometheusLibrdKafkaExporter) updateState(snap *snapshot, name, current string, states []string, labels []string) {
+	if current == "" {
+		return
+	}
+	known := false
+	for _, state := range states {
+		value := 0.0
+		if state == current {
+			value, known = 1, true
 		}
+		p.updateMetric(snap, name, value, withLabels(labels, state))
+	}
+	if !known {
+		p.updateMetric(snap, name, 1, withLabels(labels, current))
 	}
-	return nil
 }
 
-func (p *PrometheusLibrdKafkaExporter) UpdateMetric(key string, value interface{}, labels []string) {
+// updateMetric adds the value of a known metric to the snapshot being built.
+func (p *PrometheusLibrdKafkaExporter) updateMetric(snap *snapshot, key string, value float64, labels []string) {
 	if metric, ok := p.Metrics[key]; ok {
-		switch metric.(type) {
-		case *prometheus.GaugeVec:
-			gauge := metric.(*prometheus.GaugeVec)
-			gauge.WithLabelValues(labels...).Set(value.(float64))
-		case *prometheus.CounterVec:
-			valueKey := key
-			for _, lab := range labels {
-				valueKey = valueKey + lab
-			}
-			p.MapMutex.Lock()
-			counter := metric.(*prometheus.CounterVec)
-			var increment float64
-			if m, ok := p.MetricsValues[valueKey]; ok {
-				increment = value.(float64) - m
-			} else {
-				increment = value.(float64)
-			}
-			if increment > 0 {
-				counter.WithLabelValues(labels...).Add(increment)
-			}
-			p.MetricsValues[valueKey] = value.(float64)
-			p.MapMutex.Unlock()
+		snap.samples = append(snap.samples, sample{metric: metric, value: value, labels: labels})
+	}
+}
+
+// updateWindow adds a window stat to the snapshot being built, as one gauge per
+// window field, a summary or a native histogram depending on WindowStats.
+func (p *PrometheusLibrdKafkaExporter) updateWindow(snap *snapshot, key string, window *stats.Window, labels []string) {
+	metric, ok := p.Windows[key]
+	switch {
+	case p.WindowStats == WINDOW_SUMMARY && ok:
+		totals := snap.windowTotals(key, labels, window)
+		snap.samples = append(snap.samples, sample{metric: metric, window: window, totals: totals, labels: labels})
+	case p.WindowStats == WINDOW_NATIVE && ok:
+		hist := snap.histogram(key, labels)
+		hist.observeWindow(window)
+		snap.samples = append(snap.samples, sample{metric: metric, histogram: hist, labels: labels})
+	default:
+		for k, value := range stats.Numbers(window) {
+			p.updateMetric(snap, key+"_"+k, value, labels)
 		}
 	}
 }
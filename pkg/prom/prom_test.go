@@ -0,0 +1,360 @@
+package prom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// brokerWindows returns the non empty rtt and int_latency windows of the brokers of the fixture, by broker.
+func brokerWindows(t *testing.T, s *stats.Stats) map[string]map[string]*stats.Window {
+	t.Helper()
+	windows := make(map[string]map[string]*stats.Window)
+	for name, b := range s.Brokers {
+		for window, w := range map[string]*stats.Window{"rtt": b.Rtt, "int_latency": b.IntLatency} {
+			if w == nil || w.Cnt == 0 {
+				continue
+			}
+			if windows[name] == nil {
+				windows[name] = make(map[string]*stats.Window)
+			}
+			windows[name][window] = w
+		}
+	}
+	if len(windows["localhost:9092/2"]) != 2 {
+		t.Fatalf("missing rtt and int_latency windows in the fixture: %v", windows)
+	}
+	return windows
+}
+
+func TestWindowStatsGauges(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	s := loadFixture(t)
+	if err := exp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	for broker, windows := range brokerWindows(t, s) {
+		for window, w := range windows {
+			for field, want := range map[string]int64{
+				"min": w.Min, "max": w.Max, "avg": w.Avg, "sum": w.Sum, "cnt": w.Cnt,
+				"p50": w.P50, "p99": w.P99, "p99_99": w.P99_99,
+			} {
+				name := "librdkafka_brokers_" + window + "_" + field
+				m := find(families[name], map[string]string{"broker": broker})
+				if m == nil {
+					t.Errorf("%s{broker=%q} not exported", name, broker)
+					continue
+				}
+				if got := value(m); got != float64(want) {
+					t.Errorf("%s{broker=%q}: got %v, want %v", name, broker, got, want)
+				}
+			}
+		}
+	}
+	if _, ok := families["librdkafka_brokers_rtt"]; ok {
+		t.Error("summary exported in gauges mode")
+	}
+}
+
+func TestWindowStatsSummary(t *testing.T) {
+	settings := DefaultSettings()
+	settings.WindowStats = WINDOW_SUMMARY
+	exp := newTestExporter(t, settings)
+	s := loadFixture(t)
+	// Two pushes of the same windows: the quantiles are those of the last window,
+	// the count and sum accumulate so they never go backwards.
+	for i := 0; i < 2; i++ {
+		if err := exp.UpdateStats(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	families := gather(t, exp.Registry)
+	if _, ok := families["librdkafka_brokers_rtt_p99"]; ok {
+		t.Error("gauges exported in summary mode")
+	}
+	for broker, windows := range brokerWindows(t, s) {
+		for window, w := range windows {
+			name := "librdkafka_brokers_" + window
+			m := find(families[name], map[string]string{"broker": broker})
+			if m == nil || m.Summary == nil {
+				t.Errorf("%s{broker=%q} summary not exported", name, broker)
+				continue
+			}
+			if got, want := m.Summary.GetSampleCount(), 2*uint64(w.Cnt); got != want {
+				t.Errorf("%s{broker=%q} count: got %v, want %v", name, broker, got, want)
+			}
+			if got, want := m.Summary.GetSampleSum(), 2*float64(w.Sum); got != want {
+				t.Errorf("%s{broker=%q} sum: got %v, want %v", name, broker, got, want)
+			}
+			quantiles := map[float64]float64{0.5: float64(w.P50), 0.75: float64(w.P75), 0.9: float64(w.P90),
+				0.95: float64(w.P95), 0.99: float64(w.P99), 0.9999: float64(w.P99_99)}
+			if len(m.Summary.Quantile) != len(quantiles) {
+				t.Errorf("%s{broker=%q}: got %d quantiles, want %d", name, broker, len(m.Summary.Quantile), len(quantiles))
+			}
+			for _, q := range m.Summary.Quantile {
+				if want := quantiles[q.GetQuantile()]; q.GetValue() != want {
+					t.Errorf("%s{broker=%q,quantile=%v}: got %v, want %v", name, broker, q.GetQuantile(), q.GetValue(), want)
+				}
+			}
+		}
+	}
+}
+
+func TestWindowStatsNative(t *testing.T) {
+	settings := DefaultSettings()
+	settings.WindowStats = WINDOW_NATIVE
+	exp := newTestExporter(t, settings)
+	s := loadFixture(t)
+	for i := 0; i < 2; i++ {
+		if err := exp.UpdateStats(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	families := gather(t, exp.Registry)
+	for broker, windows := range brokerWindows(t, s) {
+		for window, w := range windows {
+			name := "librdkafka_brokers_" + window
+			m := find(families[name], map[string]string{"broker": broker})
+			if m == nil || m.Histogram == nil {
+				t.Errorf("%s{broker=%q} histogram not exported", name, broker)
+				continue
+			}
+			h := m.Histogram
+			if got, want := h.GetSampleCount(), 2*uint64(w.Cnt); got != want {
+				t.Errorf("%s{broker=%q} count: got %v, want %v", name, broker, got, want)
+			}
+			if got, want := h.GetSampleSum(), 2*float64(w.Sum); got != want {
+				t.Errorf("%s{broker=%q} sum: got %v, want %v", name, broker, got, want)
+			}
+			// The buckets hold every value of the windows.
+			var buckets, count int64
+			for _, delta := range h.PositiveDelta {
+				count += delta
+				buckets += count
+			}
+			if got, want := uint64(buckets)+h.GetZeroCount(), 2*uint64(w.Cnt); got != want {
+				t.Errorf("%s{broker=%q} buckets: got %v values, want %v", name, broker, got, want)
+			}
+		}
+	}
+}
+
+// fixtureKey is the key of the snapshot of the fixture client.
+const fixtureKey = "rdkafka/rdkafka#producer-1/producer"
+
+// seen reports whether the fixture client is gathered after its last push is moved back by ago.
+func seen(t *testing.T, exp *PrometheusLibrdKafkaExporter, key string, ago time.Duration) bool {
+	t.Helper()
+	exp.MapMutex.Lock()
+	if snap := exp.Snapshots[key]; snap != nil {
+		snap.lastSeen = time.Now().Add(-ago)
+	}
+	exp.MapMutex.Unlock()
+	return find(gather(t, exp.Registry)["librdkafka_exporter_client_last_seen_timestamp_seconds"], nil) != nil
+}
+
+// Clients are dropped after GraceMultiplier intervals without stats, the interval being
+// StatsInterval until it is estimated from the ts of two pushes.
+func TestExpireSnapshots(t *testing.T) {
+	settings := DefaultSettings()
+	settings.StatsInterval, settings.GraceMultiplier = 10*time.Second, 2
+	exp := newTestExporter(t, settings)
+	s := loadFixture(t)
+	if err := exp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	if !seen(t, exp, fixtureKey, 19*time.Second) {
+		t.Fatal("client expired before 2 assumed intervals")
+	}
+	if seen(t, exp, fixtureKey, 21*time.Second) {
+		t.Fatal("client not expired after 2 assumed intervals")
+	}
+
+	// Pushes 3s apart.
+	for _, ts := range []int64{s.Ts, s.Ts + 3_000_000} {
+		next := loadFixture(t)
+		next.Ts = ts
+		if err := exp.UpdateStats(next); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := exp.Snapshots[fixtureKey].interval; got != 3*time.Second {
+		t.Fatalf("got interval %v, want 3s", got)
+	}
+	// A restart keeps the interval, the ts of the new instance can't be compared.
+	restarted := loadFixture(t)
+	restarted.Ts, restarted.Age = 1_000, 1_000
+	if err := exp.UpdateStats(restarted); err != nil {
+		t.Fatal(err)
+	}
+	if got := exp.Snapshots[fixtureKey].interval; got != 3*time.Second {
+		t.Fatalf("after a restart: got interval %v, want 3s", got)
+	}
+	if !seen(t, exp, fixtureKey, 5*time.Second) {
+		t.Error("client expired before 2 estimated intervals")
+	}
+	if seen(t, exp, fixtureKey, 7*time.Second) {
+		t.Error("client not expired after 2 estimated intervals")
+	}
+
+	// Clients pushed to a group never expire.
+	if err := exp.UpdateGroupStats(Group{"job": "app"}, loadFixture(t), ClientInfo{}, true); err != nil {
+		t.Fatal(err)
+	}
+	if !seen(t, exp, fixtureKey+`{job="app"}`, time.Hour) {
+		t.Error("grouped client expired")
+	}
+}
+
+// The exporter is where the stats of every transport are checked to identify their client.
+func TestUpdateRejectsStatsWithoutClient(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	for _, s := range []*stats.Stats{{}, {Name: "app#producer-1"}, {Type: "producer"}} {
+		if err := exp.UpdateStats(s); !errors.Is(err, stats.ErrNoClient) {
+			t.Errorf("%+v: got error %v, want %v", s, err, stats.ErrNoClient)
+		}
+	}
+	if err := exp.UpdateStats(nil); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("nil: got error %v, want %v", err, stats.ErrEmpty)
+	}
+	if n := len(exp.Snapshots); n != 0 {
+		t.Errorf("got %d clients, want 0", n)
+	}
+}
+
+// states returns the values of a StateSet by state, for the series having all the given labels.
+func states(mf *dto.MetricFamily, label string, labels map[string]string) map[string]float64 {
+	values := make(map[string]float64)
+	if mf == nil {
+		return values
+	}
+	for _, m := range mf.Metric {
+		matched, state := 0, ""
+		for _, l := range m.Label {
+			if v, ok := labels[l.GetName()]; ok && v == l.GetValue() {
+				matched++
+			}
+			if l.GetName() == label {
+				state = l.GetValue()
+			}
+		}
+		if matched == len(labels) {
+			values[state] = value(m)
+		}
+	}
+	return values
+}
+
+// A state is exported as a StateSet, the current state set to 1 and the other known ones to 0.
+func TestStateSet(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	known := 0
+	for _, m := range exp.Mappings.Metrics {
+		if m.Value == "brokers" {
+			for _, child := range m.Metrics {
+				if child.Value == "state" {
+					known = len(child.States)
+				}
+			}
+		}
+	}
+	if known == 0 {
+		t.Fatal("broker state mapping not found")
+	}
+	broker := map[string]string{"broker": "localhost:9092/2"}
+
+	s := loadFixture(t)
+	if err := exp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	got := states(gather(t, exp.Registry)["librdkafka_brokers_state"], "state", broker)
+	if len(got) != known || got["UP"] != 1 {
+		t.Errorf("got %v, want %d states with UP set", got, known)
+	}
+	for state, v := range got {
+		if state != "UP" && v != 0 {
+			t.Errorf("%s: got %v, want 0", state, v)
+		}
+	}
+
+	// An unknown state is added to the known ones, and a broker without state has no series.
+	b := s.Brokers["localhost:9092/2"]
+	b.State = "RECONNECTING"
+	s.Brokers["localhost:9092/2"] = b
+	other := s.Brokers["localhost:9093/3"]
+	other.State = ""
+	s.Brokers["localhost:9093/3"] = other
+	if err := exp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	got = states(families["librdkafka_brokers_state"], "state", broker)
+	if len(got) != known+1 || got["RECONNECTING"] != 1 || got["UP"] != 0 {
+		t.Errorf("got %v, want %d states with RECONNECTING set", got, known+1)
+	}
+	if got := states(families["librdkafka_brokers_state"], "state", map[string]string{"broker": "localhost:9093/3"}); len(got) != 0 {
+		t.Errorf("broker without state: got %v", got)
+	}
+
+	// The partition fetch states are labeled with the field name.
+	got = states(families["librdkafka_topics_partitions_fetch_state"], "fetch_state", map[string]string{"topic": "test", "partition": "0"})
+	if got["none"] != 1 {
+		t.Errorf("fetch state: got %v, want none set", got)
+	}
+}
+
+// Every client has a client_info series describing it, replaced on every push, and the time of its last stats.
+func TestClientInfo(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	s := loadFixture(t)
+	info := ClientInfo{UserAgent: "confluent-kafka-go/2.3.0", RemoteAddr: "10.0.0.1", LibrdkafkaVersion: "2.3.0", LanguageVersion: "go1.22"}
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	other := loadFixture(t)
+	other.Name, other.Time = "rdkafka#producer-2", s.Time+5
+	if err := exp.UpdateClientStats(other, ClientInfo{RemoteAddr: "10.0.0.2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	families := gather(t, exp.Registry)
+	for _, tc := range []struct {
+		name   string
+		labels map[string]string
+		time   int64
+	}{
+		{s.Name, map[string]string{"user_agent": info.UserAgent, "remote_addr": info.RemoteAddr,
+			"librdkafka_version": info.LibrdkafkaVersion, "language_version": info.LanguageVersion}, s.Time},
+		{other.Name, map[string]string{"user_agent": "", "remote_addr": "10.0.0.2",
+			"librdkafka_version": "", "language_version": ""}, other.Time},
+	} {
+		labels := map[string]string{"client_id": "rdkafka", "name": tc.name, "type": "producer"}
+		if m := find(families["librdkafka_client_stats_timestamp_seconds"], labels); m == nil || value(m) != float64(tc.time) {
+			t.Errorf("%s: got stats timestamp %v, want %d", tc.name, m, tc.time)
+		}
+		for name, value := range tc.labels {
+			labels[name] = value
+		}
+		if m := find(families["librdkafka_client_info"], labels); m == nil || value(m) != 1 {
+			t.Errorf("%s: got client info %v, want %v", tc.name, m, labels)
+		}
+	}
+
+	// A new push replaces the info of the client.
+	info.LibrdkafkaVersion = "2.4.0"
+	if err := exp.UpdateClientStats(s, info); err != nil {
+		t.Fatal(err)
+	}
+	infos := gather(t, exp.Registry)["librdkafka_client_info"]
+	if n := len(infos.GetMetric()); n != 2 {
+		t.Errorf("got %d client info series, want 2", n)
+	}
+	if find(infos, map[string]string{"name": s.Name, "librdkafka_version": "2.4.0"}) == nil {
+		t.Error("client info not replaced")
+	}
+}
@@ -0,0 +1,291 @@
+package prom
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	RELABEL_REPLACE  = "replace"
+	RELABEL_KEEP     = "keep"
+	RELABEL_DROP     = "drop"
+	RELABEL_HASHMOD  = "hashmod"
+	RELABEL_HASH     = "hash"     // target set to the first 16 hex digits of the SHA-256 of the source
+	RELABEL_TRUNCATE = "truncate" // target set to the first Length characters of the source
+
+	METRIC_NAME_LABEL = model.MetricNameLabel
+)
+
+// RelabelConfig is a relabeling rule like the Prometheus metric_relabel_configs. The rules
+// run on the series built from the stats before they are stored, so the dropped series never
+// reach the Registry. Labels can't be added or removed: a rule only sets the value of a label
+// of the series, and is skipped for the series without it.
+type RelabelConfig struct {
+	// SourceLabels are joined with Separator to the value matched by Regex, __name__ is the metric name.
+	SourceLabels []string `yaml:"source_labels,flow" toml:"source_labels"`
+	Separator    string   `yaml:"separator,omitempty" toml:"separator"`
+	// Regex is fully anchored, (.*) when empty.
+	Regex       string `yaml:"regex,omitempty" toml:"regex"`
+	TargetLabel string `yaml:"target_label,omitempty" toml:"target_label"`
+	// Replacement is expanded with the Regex groups, $1 when empty.
+	Replacement string `yaml:"replacement,omitempty" toml:"replacement"`
+	// Action is replace, keep, drop, hashmod, hash or truncate, replace when empty.
+	Action  string `yaml:"action,omitempty" toml:"action"`
+	Modulus uint64 `yaml:"modulus,omitempty" toml:"modulus"`
+	Length  int    `yaml:"length,omitempty" toml:"length"`
+}
+
+// relabelRule is a compiled RelabelConfig.
+type relabelRule struct {
+	RelabelConfig
+	regex *regexp.Regexp
+	// client rules only read and write ROOT_LABELS, so they also run once per push on the client labels.
+	client bool
+}
+
+// Relabeler applies relabeling rules to the series of the clients. A nil Relabeler keeps all the series.
+// The rules run in the configured order on every series. Only client rules set ROOT_LABELS, so
+// running the client rules alone on the client labels gives the ROOT_LABELS of all its series.
+type Relabeler struct {
+	rules  []*relabelRule
+	client []*relabelRule
+	series bool // some rules read or write other labels than ROOT_LABELS
+}
+
+// NewRelabeler compiles relabeling rules, reporting every invalid rule.
+func NewRelabeler(configs []RelabelConfig) (*Relabeler, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	r := &Relabeler{}
+	var errs []error
+	for i, c := range configs {
+		rule, err := compileRelabel(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("relabel[%d]: %w", i, err))
+			continue
+		}
+		r.rules = append(r.rules, rule)
+		if rule.client {
+			r.client = append(r.client, rule)
+		} else {
+			r.series = true
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return r, nil
+}
+
+// Validate checks a relabeling rule, reporting every problem found.
+func (c RelabelConfig) Validate() error {
+	_, err := compileRelabel(c)
+	return err
+}
+
+func compileRelabel(c RelabelConfig) (*relabelRule, error) {
+	if c.Separator == "" {
+		c.Separator = ";"
+	}
+	if c.Regex == "" {
+		c.Regex = "(.*)"
+	}
+	if c.Replacement == "" {
+		c.Replacement = "$1"
+	}
+	if c.Action == "" {
+		c.Action = RELABEL_REPLACE
+	}
+	var errs []error
+	errorf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	var regex *regexp.Regexp
+	if _, err := regexp.Compile(c.Regex); err != nil {
+		errorf("invalid regex: %v", err)
+	} else {
+		regex = regexp.MustCompile("^(?:" + c.Regex + ")$")
+	}
+	for _, name := range c.SourceLabels {
+		if name != METRIC_NAME_LABEL && !model.LabelName(name).IsValid() {
+			errorf("invalid source label %q", name)
+		}
+	}
+	switch c.Action {
+	case RELABEL_KEEP, RELABEL_DROP:
+		if len(c.SourceLabels) == 0 {
+			errorf("source_labels required by action %s", c.Action)
+		}
+		if c.TargetLabel != "" {
+			errorf("target_label not allowed with action %s", c.Action)
+		}
+	case RELABEL_REPLACE, RELABEL_HASHMOD, RELABEL_HASH, RELABEL_TRUNCATE:
+		if len(c.SourceLabels) == 0 && c.Action != RELABEL_REPLACE {
+			errorf("source_labels required by action %s", c.Action)
+		}
+		switch {
+		case c.TargetLabel == "":
+			errorf("target_label required by action %s", c.Action)
+		case c.TargetLabel == METRIC_NAME_LABEL:
+			errorf("metrics can't be renamed, use the mappings")
+		case !model.LabelName(c.TargetLabel).IsValid():
+			errorf("invalid target label %q", c.TargetLabel)
+		}
+	default:
+		errorf("unknown action %q, expected %s, %s, %s, %s, %s or %s", c.Action,
+			RELABEL_REPLACE, RELABEL_KEEP, RELABEL_DROP, RELABEL_HASHMOD, RELABEL_HASH, RELABEL_TRUNCATE)
+	}
+	if c.Action == RELABEL_HASHMOD && c.Modulus == 0 {
+		errorf("modulus required by action %s", c.Action)
+	}
+	if c.Action == RELABEL_TRUNCATE && c.Length <= 0 {
+		errorf("positive length required by action %s", c.Action)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	client := true
+	for _, name := range c.SourceLabels {
+		client = client && slices.Contains(ROOT_LABELS, name)
+	}
+	if root := slices.Contains(ROOT_LABELS, c.TargetLabel); root && !client {
+		// The labels of a client must be the same on all its series.
+		return nil, fmt.Errorf("target_label %s identifies the client, its source_labels must be in %s",
+			c.TargetLabel, strings.Join(ROOT_LABELS, ", "))
+	} else if c.TargetLabel != "" && !root {
+		client = false
+	}
+	return &relabelRule{RelabelConfig: c, regex: regex, client: client}, nil
+}
+
+// relabelClient applies the client rules to the ROOT_LABELS values of a client,
+// returning false when the client is dropped.
+func (r *Relabeler) relabelClient(labels []string) ([]string, bool) {
+	if r == nil {
+		return labels, true
+	}
+	return relabel(r.client, "", ROOT_LABELS, labels)
+}
+
+// seriesLabels returns the ROOT_LABELS values the series of a client are built with: the
+// relabeled ones, unless rules run on every series, from the original values.
+func (r *Relabeler) seriesLabels(original, relabeled []string) []string {
+	if r != nil && r.series {
+		return original
+	}
+	return relabeled
+}
+
+// relabelSamples applies the rules to the samples of a snapshot, when some rules don't only
+// apply to the client, removing the dropped series. Series made identical to a previous one
+// by the rules are removed too, their number is returned.
+func (r *Relabeler) relabelSamples(snap *snapshot) int {
+	if r == nil || !r.series {
+		return 0
+	}
+	samples := snap.samples[:0]
+	seen := make(map[string]bool, len(snap.samples))
+	collisions := 0
+	for _, s := range snap.samples {
+		labels, keep := relabel(r.rules, s.metric.Name, s.metric.Labels, s.labels)
+		if !keep {
+			continue
+		}
+		key := s.metric.Name + "\xff" + strings.Join(labels, "\xff")
+		if seen[key] {
+			collisions++
+			continue
+		}
+		seen[key] = true
+		s.labels = labels
+		samples = append(samples, s)
+	}
+	snap.samples = samples
+	return collisions
+}
+
+// relabel applies the rules to the label values of a series. The values are copied
+// before being changed, as samples share their label slices.
+func relabel(rules []*relabelRule, name string, names, values []string) ([]string, bool) {
+	copied := false
+	for _, rule := range rules {
+		parts := make([]string, len(rule.SourceLabels))
+		for i, source := range rule.SourceLabels {
+			parts[i] = labelValue(name, names, values, source)
+		}
+		value := strings.Join(parts, rule.Separator)
+		match := rule.regex.FindStringSubmatchIndex(value)
+		switch rule.Action {
+		case RELABEL_KEEP:
+			if match == nil {
+				return nil, false
+			}
+			continue
+		case RELABEL_DROP:
+			if match != nil {
+				return nil, false
+			}
+			continue
+		}
+		target := slices.Index(names, rule.TargetLabel)
+		if match == nil || target < 0 {
+			continue
+		}
+		var result string
+		switch rule.Action {
+		case RELABEL_REPLACE:
+			result = string(rule.regex.ExpandString(nil, rule.Replacement, value, match))
+		case RELABEL_HASHMOD:
+			sum := md5.Sum([]byte(value))
+			result = strconv.FormatUint(binary.BigEndian.Uint64(sum[8:])%rule.Modulus, 10)
+		case RELABEL_HASH:
+			sum := sha256.Sum256([]byte(value))
+			result = hex.EncodeToString(sum[:8])
+		case RELABEL_TRUNCATE:
+			result = truncate(value, rule.Length)
+		}
+		if values[target] == result {
+			continue
+		}
+		if !copied {
+			values, copied = withLabels(values), true
+		}
+		values[target] = result
+	}
+	return values, true
+}
+
+// labelValue returns the value of a label of a series, empty when the series doesn't have it.
+func labelValue(name string, names, values []string, label string) string {
+	if label == METRIC_NAME_LABEL {
+		return name
+	}
+	if i := slices.Index(names, label); i >= 0 {
+		return values[i]
+	}
+	return ""
+}
+
+// truncate returns the first length characters of s.
+func truncate(s string, length int) string {
+	for i := range s {
+		if length == 0 {
+			return s[:i]
+		}
+		length--
+	}
+	return s
+}
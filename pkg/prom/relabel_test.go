@@ -0,0 +1,124 @@
+package prom
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newRelabelExporter(t *testing.T, configs ...RelabelConfig) *PrometheusLibrdKafkaExporter {
+	t.Helper()
+	settings := DefaultSettings()
+	relabel, err := NewRelabeler(configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Relabel = relabel
+	exp := newTestExporter(t, settings)
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	return exp
+}
+
+func labelOf(m *dto.Metric, name string) (string, bool) {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue(), true
+		}
+	}
+	return "", false
+}
+
+func TestRelabelValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		rule  RelabelConfig
+		valid bool
+	}{
+		{"client", RelabelConfig{SourceLabels: []string{"name"}, Regex: "(.*)#.*", TargetLabel: "name"}, true},
+		{"constant client label", RelabelConfig{TargetLabel: "client_id", Replacement: "app"}, true},
+		{"series", RelabelConfig{SourceLabels: []string{"client_id", "topic"}, TargetLabel: "topic"}, true},
+		{"client label from a series label", RelabelConfig{SourceLabels: []string{"topic"}, TargetLabel: "client_id"}, false},
+		{"metric name", RelabelConfig{SourceLabels: []string{"topic"}, TargetLabel: METRIC_NAME_LABEL}, false},
+		{"invalid regex", RelabelConfig{SourceLabels: []string{"topic"}, Regex: "(", Action: RELABEL_DROP}, false},
+		{"hashmod without modulus", RelabelConfig{SourceLabels: []string{"topic"}, TargetLabel: "topic", Action: RELABEL_HASHMOD}, false},
+		{"truncate without length", RelabelConfig{SourceLabels: []string{"topic"}, TargetLabel: "topic", Action: RELABEL_TRUNCATE}, false},
+		{"unknown action", RelabelConfig{SourceLabels: []string{"topic"}, Action: "labelmap"}, false},
+	} {
+		if err := tc.rule.Validate(); (err == nil) != tc.valid {
+			t.Errorf("%s: got error %v, want valid %v", tc.name, err, tc.valid)
+		}
+	}
+}
+
+// The rules run in the configured order: a rule reading the client labels sees them as the
+// previous rules left them, whether they apply to the client or to every series.
+func TestRelabelOrder(t *testing.T) {
+	exp := newRelabelExporter(t,
+		RelabelConfig{SourceLabels: []string{"client_id", "topic"}, Regex: "rdkafka;.+", Action: RELABEL_KEEP},
+		RelabelConfig{TargetLabel: "client_id", Replacement: "renamed"},
+		RelabelConfig{SourceLabels: []string{"client_id", "topic"}, Regex: "renamed;.+", Action: RELABEL_KEEP},
+	)
+	families := gather(t, exp.Registry)
+	if m := find(families["librdkafka_topics_age"], map[string]string{"client_id": "renamed", "topic": "test"}); m == nil {
+		t.Error("topic series relabeled in the wrong order")
+	}
+	if mf := families["librdkafka_brokers_rtt_avg"]; mf != nil {
+		t.Errorf("series without topic kept: %v", mf.Metric)
+	}
+	if m := find(families["librdkafka_exporter_client_restarts_total"], map[string]string{"client_id": "renamed"}); m == nil {
+		t.Error("client labels not relabeled")
+	}
+}
+
+// Series made identical by the rules are dropped and counted, so the Registry still gathers.
+func TestRelabelCollisions(t *testing.T) {
+	// partitionSeries returns the number of partition series gathered.
+	partitionSeries := func(families map[string]*dto.MetricFamily) int {
+		n := 0
+		for _, mf := range families {
+			for _, m := range mf.Metric {
+				if _, ok := labelOf(m, "partition"); ok {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	plain := partitionSeries(gather(t, newRelabelExporter(t).Registry))
+
+	// The partitions of a topic only differ by their partition, broker and leader labels.
+	collapse := func(rule RelabelConfig) []RelabelConfig {
+		var rules []RelabelConfig
+		for _, label := range []string{"partition", "broker", "leader"} {
+			rule.SourceLabels, rule.TargetLabel = []string{label}, label
+			rules = append(rules, rule)
+		}
+		return rules
+	}
+	for _, rules := range [][]RelabelConfig{
+		collapse(RelabelConfig{Replacement: "all"}),
+		collapse(RelabelConfig{Action: RELABEL_HASHMOD, Modulus: 1}),
+	} {
+		rule := rules[0]
+		exp := newRelabelExporter(t, rules...)
+		// A second push counts the collisions again.
+		if err := exp.UpdateStats(loadFixture(t)); err != nil {
+			t.Fatal(err)
+		}
+		families := gather(t, exp.Registry)
+		want := plain - partitionSeries(families)
+		if want <= 0 {
+			t.Fatalf("%v: no series collided", rule)
+		}
+		m := find(families["librdkafka_exporter_relabel_collisions_total"], map[string]string{"client_id": "rdkafka"})
+		if m == nil {
+			t.Errorf("%v: collisions not counted", rule)
+			continue
+		}
+		if collisions := value(m); collisions != float64(2*want) {
+			t.Errorf("%v: got %v collisions, want %v", rule, collisions, 2*want)
+		}
+	}
+}
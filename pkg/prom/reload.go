@@ -0,0 +1,136 @@
+package prom
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Reload replaces the mappings and the settings of a running exporter. The metrics are rebuilt
+// from the mappings: unchanged metrics keep their series, and so their counter bases, while removed
+// and changed metrics are dropped from the snapshots. The exporter is registered again, so the
+// Registry checks the new metrics against the other collectors. On failure nothing is changed.
+func (p *PrometheusLibrdKafkaExporter) Reload(mappings *Mappings, settings Settings) error {
+	reload, err := p.PrepareReload(mappings, settings)
+	if err != nil {
+		return err
+	}
+	return reload.Commit()
+}
+
+// PreparedReload is a reload of an exporter built but not applied yet, so that the reloads of
+// several exporters are applied together: all of them are committed, or the committed ones rolled back.
+type PreparedReload struct {
+	exporter   *PrometheusLibrdKafkaExporter
+	next, prev *PrometheusLibrdKafkaExporter
+	settings   Settings
+	generation int // generation of the exporter the reload was built from
+
+	added, removed, changed int
+}
+
+// PrepareReload builds the metrics of the mappings, see Reload. The exporter is not changed.
+func (p *PrometheusLibrdKafkaExporter) PrepareReload(mappings *Mappings, settings Settings) (*PreparedReload, error) {
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
+	r := &PreparedReload{
+		exporter: p,
+		next: &PrometheusLibrdKafkaExporter{
+			Prefix:  p.Prefix,
+			Metrics: make(map[string]*MetricDesc),
+			Windows: make(map[string]*MetricDesc),
+		},
+		settings: settings,
+	}
+	if err := r.next.BuildMetrics(mappings); err != nil {
+		return nil, err
+	}
+	p.MapMutex.RLock()
+	defer p.MapMutex.RUnlock()
+	r.generation = p.generation
+	r.prev = &PrometheusLibrdKafkaExporter{Mappings: p.Mappings, Metrics: p.Metrics, Windows: p.Windows, Settings: p.Settings}
+	for name, metric := range r.next.Metrics {
+		old, ok := p.Metrics[name]
+		switch {
+		case !ok:
+			r.added++
+		case old.ValueType == metric.ValueType && old.Desc.String() == metric.Desc.String():
+			r.next.Metrics[name] = old
+		default:
+			r.changed++
+		}
+	}
+	for name := range p.Metrics {
+		if _, ok := r.next.Metrics[name]; !ok {
+			r.removed++
+		}
+	}
+	for name, window := range r.next.Windows {
+		if old, ok := p.Windows[name]; ok && old.Desc.String() == window.Desc.String() {
+			r.next.Windows[name] = old
+		}
+	}
+	return r, nil
+}
+
+// Commit applies the reload. It fails when the reloaded metrics can't be registered, or when the
+// exporter was reloaded since the reload was prepared, leaving the exporter unchanged.
+func (r *PreparedReload) Commit() error {
+	p := r.exporter
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
+	p.MapMutex.RLock()
+	generation := p.generation
+	p.MapMutex.RUnlock()
+	if generation != r.generation {
+		return errors.New("exporter reloaded concurrently")
+	}
+	p.Registerer.Unregister(p)
+	p.swap(r.next, r.settings)
+	if err := p.Registerer.Register(p); err != nil {
+		p.swap(r.prev, r.prev.Settings)
+		if err := p.Registerer.Register(p); err != nil {
+			log.Println("Registering the exporter back: ", err)
+		}
+		return fmt.Errorf("registering the reloaded metrics: %w", err)
+	}
+	log.Printf("Metrics reloaded: %d added, %d removed, %d changed", r.added, r.removed, r.changed)
+	return nil
+}
+
+// Rollback restores the metrics and the settings a committed reload replaced.
+func (r *PreparedReload) Rollback() {
+	p := r.exporter
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
+	p.Registerer.Unregister(p)
+	p.swap(r.prev, r.prev.Settings)
+	if err := p.Registerer.Register(p); err != nil {
+		log.Println("Registering the exporter back: ", err)
+	}
+	log.Println("Metrics reload rolled back")
+}
+
+// swap replaces the metrics and the settings of the exporter, starting a new generation.
+func (p *PrometheusLibrdKafkaExporter) swap(next *PrometheusLibrdKafkaExporter, settings Settings) {
+	p.MapMutex.Lock()
+	defer p.MapMutex.Unlock()
+	p.Mappings, p.Metrics, p.Windows = next.Mappings, next.Metrics, next.Windows
+	p.Settings = settings
+	p.generation++
+}
+
+// pruneSamples drops the samples of the metrics removed or changed since the snapshot was built. MapMutex must be held.
+func (p *PrometheusLibrdKafkaExporter) pruneSamples(snap *snapshot) {
+	samples := snap.samples[:0]
+	for _, s := range snap.samples {
+		if p.Metrics[s.metric.Name] == s.metric || p.Windows[s.metric.Name] == s.metric {
+			samples = append(samples, s)
+		}
+	}
+	snap.samples = samples
+	snap.generation = p.generation
+}
@@ -0,0 +1,126 @@
+package prom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newBrokersExporter(t *testing.T) *PrometheusLibrdKafkaExporter {
+	t.Helper()
+	mappings, err := DefaultMappings().Select([]string{"brokers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp, err := NewPrometheusLibrdKafkaExporterWithOptions(Options{Mappings: mappings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp.Settings = DefaultSettings()
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	return exp
+}
+
+// topicMetrics returns the number of topic metrics gathered from an exporter.
+func topicMetrics(t *testing.T, exp *PrometheusLibrdKafkaExporter) int {
+	t.Helper()
+	n := 0
+	for name := range gather(t, exp.Registry) {
+		if strings.HasPrefix(name, "librdkafka_topics_") {
+			n++
+		}
+	}
+	return n
+}
+
+// descCollector describes a metric it never collects.
+type descCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c *descCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+func (c *descCollector) Collect(chan<- prometheus.Metric)    {}
+
+func TestPreparedReload(t *testing.T) {
+	exp := newBrokersExporter(t)
+	txBefore := value(find(gather(t, exp.Registry)["librdkafka_brokers_tx"], map[string]string{"broker": "localhost:9092/2"}))
+
+	reload, err := exp.PrepareReload(DefaultMappings(), DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	if n := topicMetrics(t, exp); n != 0 {
+		t.Fatalf("prepared reload applied: %d topic metrics", n)
+	}
+
+	if err := reload.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	families := gather(t, exp.Registry)
+	if n := topicMetrics(t, exp); n == 0 {
+		t.Error("reloaded metrics not exported")
+	}
+	// The unchanged metrics keep their series.
+	if tx := value(find(families["librdkafka_brokers_tx"], map[string]string{"broker": "localhost:9092/2"})); tx != txBefore {
+		t.Errorf("tx: got %v, want %v", tx, txBefore)
+	}
+
+	reload.Rollback()
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	if n := topicMetrics(t, exp); n != 0 {
+		t.Errorf("rolled back reload still exports %d topic metrics", n)
+	}
+}
+
+func TestPreparedReloadCommitFails(t *testing.T) {
+	exp := newBrokersExporter(t)
+	metrics := exp.Metrics
+
+	// A collector already registers a metric of the reloaded mappings.
+	conflict := &descCollector{newTestExporter(t, DefaultSettings()).Metrics["librdkafka_topics_age"].Desc}
+	exp.Registry.MustRegister(conflict)
+	reload, err := exp.PrepareReload(DefaultMappings(), DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reload.Commit(); err == nil {
+		t.Fatal("expected a registration error")
+	}
+	if len(exp.Metrics) != len(metrics) {
+		t.Errorf("got %d metrics after a failed reload, want %d", len(exp.Metrics), len(metrics))
+	}
+	if err := exp.UpdateStats(loadFixture(t)); err != nil {
+		t.Fatal(err)
+	}
+	gather(t, exp.Registry)
+
+	// Reloads prepared before another reload is applied are stale.
+	if !exp.Registry.Unregister(conflict) {
+		t.Fatal("conflict not unregistered")
+	}
+	first, err := exp.PrepareReload(DefaultMappings(), DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := exp.PrepareReload(DefaultMappings(), DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Commit(); err == nil {
+		t.Error("expected an error committing a stale reload")
+	}
+}
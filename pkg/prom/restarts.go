@@ -0,0 +1,54 @@
+package prom
+
+import (
+	"log"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// restarted reports whether the snapshot comes from a new instance of the client
+// that pushed prev: librdkafka age and ts only move forward within an instance.
+func (s *snapshot) restarted(prev *snapshot) bool {
+	if s.age > 0 && prev.age > 0 && s.age < prev.age {
+		return true
+	}
+	return s.ts > 0 && s.ts < prev.ts
+}
+
+// counterKey identifies a counter series of a client.
+func counterKey(smp *sample) string {
+	return smp.metric.Name + "/" + strings.Join(smp.labels, "/")
+}
+
+// rebaseCounters keeps the counters of a client monotonic across restarts. When a new
+// instance is detected, the totals last exported for the previous instances become the
+// base added to the totals of the new one, including the totals of the series missing
+// from the last stats, such as a topic not produced to for an interval. prev is nil for
+// the first stats of a client.
+func (s *snapshot) rebaseCounters(prev *snapshot) {
+	var base, last map[string]float64
+	if prev != nil {
+		s.restarts = prev.restarts
+		base, last = prev.counterBase, prev.counterLast
+		if s.restarted(prev) {
+			s.restarts++
+			log.Printf("Client %s restarted, rebasing its counters", strings.Join(s.labels, "/"))
+			base = last
+		}
+	}
+	s.counterBase = base
+	s.counterLast = make(map[string]float64, len(last))
+	for key, total := range last {
+		s.counterLast[key] = total
+	}
+	for i := range s.samples {
+		smp := &s.samples[i]
+		if smp.metric.ValueType != prometheus.CounterValue {
+			continue
+		}
+		key := counterKey(smp)
+		smp.value += base[key]
+		s.counterLast[key] = smp.value
+	}
+}
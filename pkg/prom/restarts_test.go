@@ -0,0 +1,103 @@
+package prom
+
+import (
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+)
+
+// at returns the fixture stats emitted at ts by the client instance of the given age.
+func at(t *testing.T, ts, age int64) *stats.Stats {
+	t.Helper()
+	s := loadFixture(t)
+	s.Ts, s.Age = ts, age
+	return s
+}
+
+func TestRestartDetection(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		next      *stats.Stats
+		restarted bool
+	}{
+		{"forward", at(t, 20_000_000, 15_000_000), false},
+		{"age going back", at(t, 20_000_000, 1_000), true},
+		{"ts going back", at(t, 1_000, 0), true},
+		{"without ts nor age", at(t, 0, 0), false},
+	} {
+		exp := newTestExporter(t, DefaultSettings())
+		for _, s := range []*stats.Stats{at(t, 10_000_000, 5_000_000), tc.next} {
+			if err := exp.UpdateStats(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		families := gather(t, exp.Registry)
+		restarts := value(find(families["librdkafka_exporter_client_restarts_total"], nil))
+		if tc.restarted != (restarts == 1) {
+			t.Errorf("%s: got %v restarts, want restarted %v", tc.name, restarts, tc.restarted)
+		}
+		tx, want := value(find(families["librdkafka_tx"], nil)), float64(tc.next.Tx)
+		if tc.restarted {
+			want *= 2
+		}
+		if tx != want {
+			t.Errorf("%s: got tx %v, want %v", tc.name, tx, want)
+		}
+	}
+}
+
+// A counter series missing from the stats before a restart keeps its total.
+func TestRebaseMissingSeries(t *testing.T) {
+	exp := newTestExporter(t, DefaultSettings())
+	partition := map[string]string{"topic": "test", "partition": "0"}
+	txmsgs := func() float64 {
+		m := find(gather(t, exp.Registry)["librdkafka_topics_partitions_txmsgs"], partition)
+		if m == nil {
+			return -1
+		}
+		return value(m)
+	}
+	first := at(t, 10_000_000, 5_000_000)
+	v := float64(first.Topics["test"].Partitions["0"].TxMsgs)
+	if v == 0 {
+		t.Fatal("no txmsgs in the fixture")
+	}
+
+	// without returns the stats of at without the partition.
+	without := func(ts, age int64) *stats.Stats {
+		s := at(t, ts, age)
+		delete(s.Topics["test"].Partitions, "0")
+		return s
+	}
+	// The partition is missing from the stats of an interval before and after restarts.
+	for i, step := range []struct {
+		s    *stats.Stats
+		want float64
+	}{
+		{first, v},
+		{without(11_000_000, 6_000_000), -1},
+		{at(t, 12_000_000, 7_000_000), v},
+		{at(t, 1_000, 1_000), 2 * v},
+		{without(2_000, 2_000), -1},
+		{at(t, 3_000, 3_000), 2 * v},
+		{at(t, 1_000, 1_000), 3 * v},
+	} {
+		if err := exp.UpdateStats(step.s); err != nil {
+			t.Fatal(err)
+		}
+		if got := txmsgs(); got != step.want {
+			t.Errorf("step %d: got txmsgs %v, want %v", i, got, step.want)
+		}
+	}
+
+	// Missing before the restart.
+	exp = newTestExporter(t, DefaultSettings())
+	for _, s := range []*stats.Stats{first, without(11_000_000, 6_000_000), at(t, 1_000, 1_000)} {
+		if err := exp.UpdateStats(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := txmsgs(); got != 2*v {
+		t.Errorf("missing before the restart: got txmsgs %v, want %v", got, 2*v)
+	}
+}
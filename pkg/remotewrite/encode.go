@@ -0,0 +1,184 @@
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// label is a prompb.Label.
+type label struct {
+	name, value string
+}
+
+// series is a prompb.TimeSeries holding a single float sample or native histogram.
+type series struct {
+	labels    []label
+	value     float64
+	histogram *dto.Histogram
+	timestamp int64 // milliseconds
+}
+
+// toSeries flattens gathered metrics into series, the way Prometheus stores a scrape.
+// External labels are added to the series not already having them.
+func toSeries(families []*dto.MetricFamily, external []label, timestamp int64) []series {
+	var out []series
+	for _, mf := range families {
+		name := mf.GetName()
+		for _, m := range mf.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			add := func(name string, value float64, h *dto.Histogram, extra ...label) {
+				out = append(out, series{labels: labelsOf(name, m.GetLabel(), external, extra...), value: value, histogram: h, timestamp: ts})
+			}
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue(), nil)
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue(), nil)
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue(), nil)
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add(name, q.GetValue(), nil, label{"quantile", formatFloat(q.GetQuantile())})
+				}
+				add(name+"_sum", s.GetSampleSum(), nil)
+				add(name+"_count", float64(s.GetSampleCount()), nil)
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				h := m.GetHistogram()
+				if h.Schema != nil {
+					add(name, 0, h)
+					continue
+				}
+				for _, b := range h.GetBucket() {
+					add(name+"_bucket", float64(b.GetCumulativeCount()), nil, label{"le", formatFloat(b.GetUpperBound())})
+				}
+				add(name+"_bucket", float64(h.GetSampleCount()), nil, label{"le", "+Inf"})
+				add(name+"_sum", h.GetSampleSum(), nil)
+				add(name+"_count", float64(h.GetSampleCount()), nil)
+			}
+		}
+	}
+	return out
+}
+
+// labelsOf returns the labels of a series sorted by name, as remote write requires.
+func labelsOf(name string, pairs []*dto.LabelPair, external []label, extra ...label) []label {
+	labels := make([]label, 0, len(pairs)+len(external)+len(extra)+1)
+	labels = append(labels, label{"__name__", name})
+	for _, p := range pairs {
+		labels = append(labels, label{p.GetName(), p.GetValue()})
+	}
+	labels = append(labels, extra...)
+	for _, e := range external {
+		found := false
+		for _, l := range labels {
+			if l.name == e.name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			labels = append(labels, e)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	return labels
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// encodeWriteRequest encodes a prompb.WriteRequest:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; repeated Histogram histograms = 4; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(batch []series) []byte {
+	var buf []byte
+	for _, s := range batch {
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, encodeSeries(s))
+	}
+	return buf
+}
+
+func encodeSeries(s series) []byte {
+	var buf []byte
+	for _, l := range s.labels {
+		var lb []byte
+		lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.name)
+		lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.value)
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, lb)
+	}
+	if s.histogram != nil {
+		buf = protowire.AppendTag(buf, 4, protowire.BytesType)
+		return protowire.AppendBytes(buf, encodeHistogram(s.histogram, s.timestamp))
+	}
+	var sb []byte
+	sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+	sb = protowire.AppendFixed64(sb, math.Float64bits(s.value))
+	sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+	sb = protowire.AppendVarint(sb, uint64(s.timestamp))
+	buf = protowire.AppendTag(buf, 2, protowire.BytesType)
+	return protowire.AppendBytes(buf, sb)
+}
+
+// encodeHistogram encodes a prompb.Histogram with integer counts:
+//
+//	Histogram  { uint64 count_int = 1; double sum = 3; sint32 schema = 4; double zero_threshold = 5;
+//	             uint64 zero_count_int = 6; repeated BucketSpan negative_spans = 8; repeated sint64 negative_deltas = 9;
+//	             repeated BucketSpan positive_spans = 11; repeated sint64 positive_deltas = 12; int64 timestamp = 15; }
+//	BucketSpan { sint32 offset = 1; uint32 length = 2; }
+func encodeHistogram(h *dto.Histogram, timestamp int64) []byte {
+	var buf []byte
+	buf = protowire.AppendTag(buf, 1, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, h.GetSampleCount())
+	buf = protowire.AppendTag(buf, 3, protowire.Fixed64Type)
+	buf = protowire.AppendFixed64(buf, math.Float64bits(h.GetSampleSum()))
+	buf = protowire.AppendTag(buf, 4, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, protowire.EncodeZigZag(int64(h.GetSchema())))
+	buf = protowire.AppendTag(buf, 5, protowire.Fixed64Type)
+	buf = protowire.AppendFixed64(buf, math.Float64bits(h.GetZeroThreshold()))
+	buf = protowire.AppendTag(buf, 6, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, h.GetZeroCount())
+	buf = appendBuckets(buf, 8, 9, h.GetNegativeSpan(), h.GetNegativeDelta())
+	buf = appendBuckets(buf, 11, 12, h.GetPositiveSpan(), h.GetPositiveDelta())
+	buf = protowire.AppendTag(buf, 15, protowire.VarintType)
+	return protowire.AppendVarint(buf, uint64(timestamp))
+}
+
+func appendBuckets(buf []byte, spansField, deltasField protowire.Number, spans []*dto.BucketSpan, deltas []int64) []byte {
+	for _, span := range spans {
+		var sb []byte
+		sb = protowire.AppendTag(sb, 1, protowire.VarintType)
+		sb = protowire.AppendVarint(sb, protowire.EncodeZigZag(int64(span.GetOffset())))
+		sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+		sb = protowire.AppendVarint(sb, uint64(span.GetLength()))
+		buf = protowire.AppendTag(buf, spansField, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, sb)
+	}
+	if len(deltas) == 0 {
+		return buf
+	}
+	var db []byte
+	for _, d := range deltas {
+		db = protowire.AppendVarint(db, protowire.EncodeZigZag(d))
+	}
+	buf = protowire.AppendTag(buf, deltasField, protowire.BytesType)
+	return protowire.AppendBytes(buf, db)
+}
@@ -0,0 +1,196 @@
+// Package remotewrite sends the exporter metrics to a Prometheus remote_write endpoint,
+// for environments where nothing scrapes the exporter.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DEFAULT_INTERVAL        = 15 * time.Second
+	DEFAULT_TIMEOUT         = 30 * time.Second
+	DEFAULT_MAX_SAMPLES     = 2000   // samples per request
+	DEFAULT_QUEUE_CAPACITY  = 500000 // samples kept in memory while the endpoint is unreachable
+	DEFAULT_MAX_RETRIES     = 5
+	DEFAULT_MIN_BACKOFF     = 100 * time.Millisecond
+	DEFAULT_MAX_BACKOFF     = 5 * time.Second
+	REMOTE_WRITE_VERSION    = "0.1.0"
+	REMOTE_WRITE_USER_AGENT = "librdkafka-prometheus-exporter"
+)
+
+// Sender gathers the metrics on every interval and sends them to a remote_write endpoint.
+// Samples are queued in memory, up to QueueCapacity, while the endpoint is unreachable,
+// the oldest ones being dropped first.
+type Sender struct {
+	URL            string
+	Interval       time.Duration
+	Timeout        time.Duration
+	ExternalLabels map[string]string
+	Headers        map[string]string
+	MaxSamples     int
+	QueueCapacity  int
+	MaxRetries     int
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+	Gatherer       prometheus.Gatherer
+
+	client   *http.Client
+	external []label
+	queue    []series
+	samples  *prometheus.CounterVec
+}
+
+// errPermanent marks the failures that retrying can't fix.
+type errPermanent struct{ error }
+
+// NewSender returns a sender with the default settings. Its own metrics, named with prefix, are registered in registerer.
+func NewSender(url, prefix string, gatherer prometheus.Gatherer, registerer prometheus.Registerer) (*Sender, error) {
+	s := &Sender{
+		URL:           url,
+		Interval:      DEFAULT_INTERVAL,
+		Timeout:       DEFAULT_TIMEOUT,
+		MaxSamples:    DEFAULT_MAX_SAMPLES,
+		QueueCapacity: DEFAULT_QUEUE_CAPACITY,
+		MaxRetries:    DEFAULT_MAX_RETRIES,
+		MinBackoff:    DEFAULT_MIN_BACKOFF,
+		MaxBackoff:    DEFAULT_MAX_BACKOFF,
+		Gatherer:      gatherer,
+		samples: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + prom.EXPORTER + "remote_write_samples_total",
+			Help: "Samples handled by the remote_write sender, by result: sent, dropped when the queue is full or rejected by the endpoint.",
+		}, []string{"result"}),
+	}
+	if err := registerer.Register(s.samples); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run sends the metrics on every interval until ctx is done.
+func (s *Sender) Run(ctx context.Context) {
+	s.start()
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.push(ctx)
+		}
+	}
+}
+
+func (s *Sender) start() {
+	s.client = &http.Client{Timeout: s.Timeout}
+	for name, value := range s.ExternalLabels {
+		s.external = append(s.external, label{name, value})
+	}
+}
+
+// push gathers the metrics, queues them and sends the queue.
+func (s *Sender) push(ctx context.Context) {
+	families, err := s.Gatherer.Gather()
+	if err != nil {
+		log.Println("Remote write gather: ", err)
+		return
+	}
+	s.enqueue(toSeries(families, s.external, time.Now().UnixMilli()))
+	if err := s.flush(ctx); err != nil {
+		log.Printf("Remote write: %v, %d samples queued", err, len(s.queue))
+	}
+}
+
+// enqueue adds samples to the queue, dropping the oldest ones above QueueCapacity.
+func (s *Sender) enqueue(batch []series) {
+	s.queue = append(s.queue, batch...)
+	if over := len(s.queue) - s.QueueCapacity; s.QueueCapacity > 0 && over > 0 {
+		s.samples.WithLabelValues("dropped").Add(float64(over))
+		s.queue = append(s.queue[:0], s.queue[over:]...)
+	}
+}
+
+// flush sends the queued samples in batches of MaxSamples. Samples rejected by the endpoint are
+// dropped, the ones that couldn't be sent after MaxRetries are kept for the next interval.
+func (s *Sender) flush(ctx context.Context) error {
+	for len(s.queue) > 0 {
+		n := min(len(s.queue), s.MaxSamples)
+		err := s.sendWithRetries(ctx, s.queue[:n])
+		var permanent errPermanent
+		switch {
+		case err == nil:
+			s.samples.WithLabelValues("sent").Add(float64(n))
+		case errors.As(err, &permanent):
+			s.samples.WithLabelValues("rejected").Add(float64(n))
+			log.Println("Remote write: ", err)
+		default:
+			return err
+		}
+		s.queue = s.queue[n:]
+	}
+	s.queue = nil
+	return nil
+}
+
+func (s *Sender) sendWithRetries(ctx context.Context, batch []series) error {
+	body := snappy.Encode(nil, encodeWriteRequest(batch))
+	backoff := s.MinBackoff
+	var err error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, s.MaxBackoff)
+		}
+		if err = s.send(ctx, body); err == nil || errors.As(err, new(errPermanent)) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Sender) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return errPermanent{err}
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", REMOTE_WRITE_USER_AGENT)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", REMOTE_WRITE_VERSION)
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 == 2 {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	err = fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(msg)))
+	// Like Prometheus, retry on server errors and throttling only.
+	if res.StatusCode/100 == 5 || res.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return errPermanent{err}
+}
@@ -0,0 +1,242 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodedSeries is a TimeSeries decoded by the fake receiver.
+type decodedSeries struct {
+	labels map[string]string
+	value  float64
+}
+
+// fakeReceiver is a remote_write endpoint answering with the given statuses in turn, 200 once
+// they are exhausted, and recording the decoded requests it accepts.
+type fakeReceiver struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	statuses []int
+	times    []time.Time // arrival of every request
+	accepted [][]decodedSeries
+}
+
+func (f *fakeReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.times = append(f.times, time.Now())
+	if len(f.statuses) > 0 {
+		status := f.statuses[0]
+		f.statuses = f.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("X-Prometheus-Remote-Write-Version") != REMOTE_WRITE_VERSION {
+		f.t.Errorf("unexpected headers: %v", r.Header)
+	}
+	compressed, _ := io.ReadAll(r.Body)
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		f.t.Errorf("snappy: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.accepted = append(f.accepted, decodeWriteRequest(f.t, body))
+}
+
+func decodeWriteRequest(t *testing.T, b []byte) []decodedSeries {
+	t.Helper()
+	var out []decodedSeries
+	fields(t, b, func(num protowire.Number, v []byte, _ uint64) {
+		if num != 1 {
+			return
+		}
+		s := decodedSeries{labels: make(map[string]string)}
+		fields(t, v, func(num protowire.Number, v []byte, _ uint64) {
+			switch num {
+			case 1:
+				var name, value string
+				fields(t, v, func(num protowire.Number, v []byte, _ uint64) {
+					if num == 1 {
+						name = string(v)
+					} else {
+						value = string(v)
+					}
+				})
+				s.labels[name] = value
+			case 2:
+				fields(t, v, func(num protowire.Number, _ []byte, n uint64) {
+					if num == 1 {
+						s.value = math.Float64frombits(n)
+					}
+				})
+			}
+		})
+		out = append(out, s)
+	})
+	return out
+}
+
+// fields calls fn with every field of a message, with the bytes of length-delimited fields or the value of the others.
+func fields(t *testing.T, b []byte, fn func(protowire.Number, []byte, uint64)) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				t.Fatal(protowire.ParseError(n))
+			}
+			fn(num, v, 0)
+			b = b[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			fn(num, nil, v)
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			fn(num, nil, v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+	}
+}
+
+// gauges returns a family of gauges labeled with topic, one per topic, all with value.
+func gauges(value float64, topics ...string) []*dto.MetricFamily {
+	name, labelName := "librdkafka_topics_age", "topic"
+	mf := &dto.MetricFamily{Name: &name, Type: dto.MetricType_GAUGE.Enum()}
+	for _, topic := range topics {
+		topic := topic
+		mf.Metric = append(mf.Metric, &dto.Metric{
+			Label: []*dto.LabelPair{{Name: &labelName, Value: &topic}},
+			Gauge: &dto.Gauge{Value: &value},
+		})
+	}
+	return []*dto.MetricFamily{mf}
+}
+
+func newTestSender(t *testing.T, receiver *fakeReceiver, gatherer prometheus.GathererFunc) *Sender {
+	t.Helper()
+	srv := httptest.NewServer(receiver)
+	t.Cleanup(srv.Close)
+	s, err := NewSender(srv.URL, "librdkafka_", gatherer, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.MinBackoff, s.MaxBackoff = 20*time.Millisecond, 30*time.Millisecond
+	return s
+}
+
+func TestSendExternalLabels(t *testing.T) {
+	receiver := &fakeReceiver{t: t}
+	s := newTestSender(t, receiver, func() ([]*dto.MetricFamily, error) { return gauges(1, "a"), nil })
+	s.ExternalLabels = map[string]string{"cluster": "c1", "topic": "external"}
+	s.start()
+	s.push(context.Background())
+
+	if len(receiver.accepted) != 1 || len(receiver.accepted[0]) != 1 {
+		t.Fatalf("got requests %v, want a single series", receiver.accepted)
+	}
+	labels := receiver.accepted[0][0].labels
+	want := map[string]string{"__name__": "librdkafka_topics_age", "topic": "a", "cluster": "c1"}
+	if len(labels) != len(want) {
+		t.Errorf("got labels %v, want %v", labels, want)
+	}
+	for name, value := range want {
+		if labels[name] != value {
+			t.Errorf("label %s: got %q, want %q", name, labels[name], value)
+		}
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	for _, tc := range []struct {
+		statuses []int
+		result   string
+		requests int
+	}{
+		{[]int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, "sent", 3},
+		{[]int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}, "sent", 4},
+		{[]int{http.StatusBadRequest}, "rejected", 1},
+	} {
+		receiver := &fakeReceiver{t: t, statuses: tc.statuses}
+		s := newTestSender(t, receiver, func() ([]*dto.MetricFamily, error) { return gauges(1, "a"), nil })
+		s.start()
+		s.push(context.Background())
+
+		if len(receiver.times) != tc.requests {
+			t.Errorf("%v: got %d requests, want %d", tc.statuses, len(receiver.times), tc.requests)
+			continue
+		}
+		// The backoff doubles up to MaxBackoff.
+		backoff := s.MinBackoff
+		for i := 1; i < len(receiver.times); i++ {
+			if gap := receiver.times[i].Sub(receiver.times[i-1]); gap < backoff {
+				t.Errorf("%v: retry %d after %v, want at least %v", tc.statuses, i, gap, backoff)
+			}
+			backoff = min(2*backoff, s.MaxBackoff)
+		}
+		if v := testutil.ToFloat64(s.samples.WithLabelValues(tc.result)); v != 1 {
+			t.Errorf("%v: got %v samples %s, want 1", tc.statuses, v, tc.result)
+		}
+		if len(s.queue) != 0 {
+			t.Errorf("%v: %d samples left in the queue", tc.statuses, len(s.queue))
+		}
+	}
+}
+
+// While the endpoint is down the samples are queued, the oldest dropped when the queue is full.
+func TestSendQueueDropsOldest(t *testing.T) {
+	down := http.StatusServiceUnavailable
+	receiver := &fakeReceiver{t: t, statuses: []int{down, down}}
+	push := 0.0
+	s := newTestSender(t, receiver, func() ([]*dto.MetricFamily, error) {
+		push++
+		return gauges(push, "a", "b"), nil
+	})
+	s.MaxRetries, s.QueueCapacity = 0, 3
+	s.start()
+
+	s.push(context.Background())
+	s.push(context.Background())
+	if len(s.queue) != 3 {
+		t.Fatalf("got %d samples queued, want 3", len(s.queue))
+	}
+	s.push(context.Background())
+
+	if len(receiver.accepted) != 1 {
+		t.Fatalf("got %d requests accepted, want 1", len(receiver.accepted))
+	}
+	var values []float64
+	for _, series := range receiver.accepted[0] {
+		values = append(values, series.value)
+	}
+	if len(values) != 3 || values[0] != 2 || values[1] != 3 || values[2] != 3 {
+		t.Errorf("got values %v, want the newest [2 3 3]", values)
+	}
+	if v := testutil.ToFloat64(s.samples.WithLabelValues("dropped")); v != 3 {
+		t.Errorf("got %v samples dropped, want 3", v)
+	}
+	if v := testutil.ToFloat64(s.samples.WithLabelValues("sent")); v != 3 {
+		t.Errorf("got %v samples sent, want 3", v)
+	}
+}
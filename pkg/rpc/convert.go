@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/statspb"
+)
+
+// Stats converts the protobuf stats to the model decoded from the stats_cb JSON.
+func Stats(s *statspb.Stats) *stats.Stats {
+	if s == nil {
+		return nil
+	}
+	out := &stats.Stats{
+		Name:             s.Name,
+		ClientID:         s.ClientId,
+		Type:             s.Type,
+		Ts:               s.Ts,
+		Time:             s.Time,
+		Age:              s.Age,
+		Replyq:           s.Replyq,
+		MsgCnt:           s.MsgCnt,
+		MsgSize:          s.MsgSize,
+		MsgMax:           s.MsgMax,
+		MsgSizeMax:       s.MsgSizeMax,
+		SimpleCnt:        s.SimpleCnt,
+		MetadataCacheCnt: s.MetadataCacheCnt,
+		Cgrp:             consumerGroup(s.Cgrp),
+		EOS:              eos(s.Eos),
+		Tx:               s.Tx,
+		TxBytes:          s.TxBytes,
+		Rx:               s.Rx,
+		RxBytes:          s.RxBytes,
+		TxMsgs:           s.Txmsgs,
+		TxMsgBytes:       s.TxmsgBytes,
+		RxMsgs:           s.Rxmsgs,
+		RxMsgBytes:       s.RxmsgBytes,
+		Extra:            extra(s.Extra),
+	}
+	if len(s.Brokers) > 0 {
+		out.Brokers = make(map[string]stats.Broker, len(s.Brokers))
+		for k, b := range s.Brokers {
+			out.Brokers[k] = broker(b)
+		}
+	}
+	if len(s.Topics) > 0 {
+		out.Topics = make(map[string]stats.Topic, len(s.Topics))
+		for k, t := range s.Topics {
+			out.Topics[k] = topic(t)
+		}
+	}
+	return out
+}
+
+func broker(b *statspb.Broker) stats.Broker {
+	out := stats.Broker{
+		Name:           b.GetName(),
+		NodeID:         b.GetNodeid(),
+		NodeName:       b.GetNodename(),
+		Source:         b.GetSource(),
+		State:          b.GetState(),
+		StateAge:       b.GetStateage(),
+		OutbufCnt:      b.GetOutbufCnt(),
+		OutbufMsgCnt:   b.GetOutbufMsgCnt(),
+		WaitrespCnt:    b.GetWaitrespCnt(),
+		WaitrespMsgCnt: b.GetWaitrespMsgCnt(),
+		Tx:             b.GetTx(),
+		TxBytes:        b.GetTxbytes(),
+		TxErrs:         b.GetTxerrs(),
+		TxRetries:      b.GetTxretries(),
+		TxIdle:         b.GetTxidle(),
+		ReqTimeouts:    b.GetReqTimeouts(),
+		Rx:             b.GetRx(),
+		RxBytes:        b.GetRxbytes(),
+		RxErrs:         b.GetRxerrs(),
+		RxCorridErrs:   b.GetRxcorriderrs(),
+		RxPartial:      b.GetRxpartial(),
+		RxIdle:         b.GetRxidle(),
+		Req:            b.GetReq(),
+		ZbufGrow:       b.GetZbufGrow(),
+		BufGrow:        b.GetBufGrow(),
+		Wakeups:        b.GetWakeups(),
+		Connects:       b.GetConnects(),
+		Disconnects:    b.GetDisconnects(),
+		IntLatency:     window(b.GetIntLatency()),
+		OutbufLatency:  window(b.GetOutbufLatency()),
+		Rtt:            window(b.GetRtt()),
+		Throttle:       window(b.GetThrottle()),
+		Extra:          extra(b.GetExtra()),
+	}
+	if toppars := b.GetToppars(); len(toppars) > 0 {
+		out.Toppars = make(map[string]stats.Toppar, len(toppars))
+		for k, tp := range toppars {
+			out.Toppars[k] = stats.Toppar{Topic: tp.GetTopic(), Partition: tp.GetPartition()}
+		}
+	}
+	return out
+}
+
+func window(w *statspb.Window) *stats.Window {
+	if w == nil {
+		return nil
+	}
+	return &stats.Window{
+		Min:        w.Min,
+		Max:        w.Max,
+		Avg:        w.Avg,
+		Sum:        w.Sum,
+		Stddev:     w.Stddev,
+		P50:        w.P50,
+		P75:        w.P75,
+		P90:        w.P90,
+		P95:        w.P95,
+		P99:        w.P99,
+		P99_99:     w.P99_99,
+		OutOfRange: w.Outofrange,
+		HdrSize:    w.Hdrsize,
+		Cnt:        w.Cnt,
+	}
+}
+
+func topic(t *statspb.Topic) stats.Topic {
+	out := stats.Topic{
+		Topic:       t.GetTopic(),
+		Age:         t.GetAge(),
+		MetadataAge: t.GetMetadataAge(),
+		BatchSize:   window(t.GetBatchsize()),
+		BatchCnt:    window(t.GetBatchcnt()),
+		Extra:       extra(t.GetExtra()),
+	}
+	if partitions := t.GetPartitions(); len(partitions) > 0 {
+		out.Partitions = make(map[string]stats.Partition, len(partitions))
+		for k, p := range partitions {
+			out.Partitions[k] = partition(p)
+		}
+	}
+	return out
+}
+
+func partition(p *statspb.Partition) stats.Partition {
+	return stats.Partition{
+		Partition:            p.GetPartition(),
+		Broker:               p.GetBroker(),
+		Leader:               p.GetLeader(),
+		Desired:              p.GetDesired(),
+		Unknown:              p.GetUnknown(),
+		MsgqCnt:              p.GetMsgqCnt(),
+		MsgqBytes:            p.GetMsgqBytes(),
+		XmitMsgqCnt:          p.GetXmitMsgqCnt(),
+		XmitMsgqBytes:        p.GetXmitMsgqBytes(),
+		FetchqCnt:            p.GetFetchqCnt(),
+		FetchqSize:           p.GetFetchqSize(),
+		FetchState:           p.GetFetchState(),
+		QueryOffset:          p.GetQueryOffset(),
+		NextOffset:           p.GetNextOffset(),
+		AppOffset:            p.GetAppOffset(),
+		StoredOffset:         p.GetStoredOffset(),
+		StoredLeaderEpoch:    p.GetStoredLeaderEpoch(),
+		CommittedOffset:      p.GetCommittedOffset(),
+		CommittedLeaderEpoch: p.GetCommittedLeaderEpoch(),
+		EOFOffset:            p.GetEofOffset(),
+		LoOffset:             p.GetLoOffset(),
+		HiOffset:             p.GetHiOffset(),
+		LsOffset:             p.GetLsOffset(),
+		ConsumerLag:          p.GetConsumerLag(),
+		ConsumerLagStored:    p.GetConsumerLagStored(),
+		LeaderEpoch:          p.GetLeaderEpoch(),
+		TxMsgs:               p.GetTxmsgs(),
+		TxBytes:              p.GetTxbytes(),
+		RxMsgs:               p.GetRxmsgs(),
+		RxBytes:              p.GetRxbytes(),
+		Msgs:                 p.GetMsgs(),
+		RxVerDrops:           p.GetRxVerDrops(),
+		MsgsInflight:         p.GetMsgsInflight(),
+		NextAckSeq:           p.GetNextAckSeq(),
+		NextErrSeq:           p.GetNextErrSeq(),
+		AckedMsgID:           p.GetAckedMsgid(),
+		Extra:                extra(p.GetExtra()),
+	}
+}
+
+func consumerGroup(c *statspb.ConsumerGroup) *stats.ConsumerGroup {
+	if c == nil {
+		return nil
+	}
+	return &stats.ConsumerGroup{
+		State:           c.State,
+		StateAge:        c.Stateage,
+		JoinState:       c.JoinState,
+		RebalanceAge:    c.RebalanceAge,
+		RebalanceCnt:    c.RebalanceCnt,
+		RebalanceReason: c.RebalanceReason,
+		AssignmentSize:  c.AssignmentSize,
+		Extra:           extra(c.Extra),
+	}
+}
+
+func eos(e *statspb.EOS) *stats.EOS {
+	if e == nil {
+		return nil
+	}
+	return &stats.EOS{
+		IdempState:    e.IdempState,
+		IdempStateAge: e.IdempStateage,
+		TxnState:      e.TxnState,
+		TxnStateAge:   e.TxnStateage,
+		TxnMayEnq:     e.TxnMayEnq,
+		ProducerID:    e.ProducerId,
+		ProducerEpoch: e.ProducerEpoch,
+		EpochCnt:      e.EpochCnt,
+		Extra:         extra(e.Extra),
+	}
+}
+
+func extra(m map[string]float64) stats.Extra {
+	if len(m) == 0 {
+		return nil
+	}
+	return stats.Extra(m)
+}
@@ -0,0 +1,155 @@
+// Package rpc implements the gRPC StatsService, feeding the stats it receives to the exporter.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/statspb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	LIBRDKAFKA_VERSION_METADATA = "x-librdkafka-version"
+	LANGUAGE_VERSION_METADATA   = "x-language-version"
+	GROUP_ID_METADATA           = "x-group-id"
+)
+
+// ExporterFunc returns the exporter the stats of a call are fed to, for instance the exporter of the
+// tenant named in its metadata. Its errors are returned to the client, so they should be status errors.
+type ExporterFunc func(ctx context.Context) (*prom.PrometheusLibrdKafkaExporter, error)
+
+// Static returns an ExporterFunc feeding the stats of every call to exporter.
+func Static(exporter *prom.PrometheusLibrdKafkaExporter) ExporterFunc {
+	return func(context.Context) (*prom.PrometheusLibrdKafkaExporter, error) {
+		return exporter, nil
+	}
+}
+
+// Server implements statspb.StatsServiceServer.
+type Server struct {
+	statspb.UnimplementedStatsServiceServer
+	Exporter ExporterFunc
+	MaxSize  func() int // max size in bytes of a request, read on every request so it can be reloaded
+}
+
+// RECV_HEADROOM is how many times the max size at start the transport accepts, so reloads can raise the max size.
+const RECV_HEADROOM = 2
+
+// NewServer returns a gRPC server with the StatsService registered, accepting requests up to maxSize() bytes.
+func NewServer(exporter ExporterFunc, maxSize func() int, opts ...grpc.ServerOption) *grpc.Server {
+	// The size of every request is checked against maxSize, the transport only bounds the memory
+	// a request takes before that check.
+	s := grpc.NewServer(append([]grpc.ServerOption{grpc.MaxRecvMsgSize(maxRecvSize(maxSize()))}, opts...)...)
+	statspb.RegisterStatsServiceServer(s, &Server{Exporter: exporter, MaxSize: maxSize})
+	return s
+}
+
+// maxRecvSize returns the size of the largest message the transport receives, RECV_HEADROOM times maxSize.
+func maxRecvSize(maxSize int) int {
+	if maxSize > math.MaxInt32/RECV_HEADROOM {
+		return math.MaxInt32
+	}
+	return maxSize * RECV_HEADROOM
+}
+
+// Push implements statspb.StatsServiceServer.
+func (s *Server) Push(ctx context.Context, req *statspb.PushRequest) (*statspb.PushResponse, error) {
+	if err := s.update(ctx, req); err != nil {
+		return nil, err
+	}
+	return &statspb.PushResponse{}, nil
+}
+
+// StreamStats implements statspb.StatsServiceServer. Rejected requests don't end the stream,
+// their errors are reported in the response.
+func (s *Server) StreamStats(stream statspb.StatsService_StreamStatsServer) error {
+	res := &statspb.StreamStatsResponse{}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.update(stream.Context(), req); err != nil {
+			res.Rejected++
+			res.Errors = append(res.Errors, status.Convert(err).Message())
+			continue
+		}
+		res.Accepted++
+	}
+}
+
+func (s *Server) update(ctx context.Context, req *statspb.PushRequest) error {
+	if s.MaxSize != nil {
+		if size, max := proto.Size(req), s.MaxSize(); size > max {
+			return status.Errorf(codes.ResourceExhausted, "request of %d bytes larger than the max size of %d bytes", size, max)
+		}
+	}
+	var st *stats.Stats
+	switch payload := req.GetPayload().(type) {
+	case *statspb.PushRequest_Stats:
+		st = Stats(payload.Stats)
+	case *statspb.PushRequest_Json:
+		var err error
+		if st, err = stats.Unmarshal(payload.Json); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if st == nil {
+		return status.Error(codes.InvalidArgument, stats.ErrEmpty.Error())
+	}
+	exp, err := s.Exporter(ctx)
+	if err != nil {
+		return err
+	}
+	if err := exp.UpdateClientStats(st, clientInfo(ctx)); err != nil {
+		// The series kept by a push partially applied because of the limits are exported.
+		var limited *prom.LimitError
+		switch {
+		case errors.Is(err, stats.ErrNoClient):
+			return status.Error(codes.InvalidArgument, err.Error())
+		case !errors.As(err, &limited):
+			return status.Error(codes.Internal, err.Error())
+		case limited.Rejected:
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+	return nil
+}
+
+// clientInfo describes the client behind a call from its metadata, as the HTTP headers do.
+func clientInfo(ctx context.Context) prom.ClientInfo {
+	var info prom.ClientInfo
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.RemoteAddr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(info.RemoteAddr); err == nil {
+			info.RemoteAddr = host
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	info.UserAgent = get("user-agent")
+	info.LibrdkafkaVersion = get(LIBRDKAFKA_VERSION_METADATA)
+	info.LanguageVersion = get(LANGUAGE_VERSION_METADATA)
+	info.GroupID = get(GROUP_ID_METADATA)
+	return info
+}
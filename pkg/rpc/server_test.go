@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/statspb"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the StatsService over an in-memory connection and returns a client.
+func newTestClient(t *testing.T, exporter ExporterFunc, maxSize func() int) statspb.StatsServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(exporter, maxSize)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return statspb.NewStatsServiceClient(conn)
+}
+
+func jsonRequest(t *testing.T) *statspb.PushRequest {
+	t.Helper()
+	payload, err := os.ReadFile("../../cmd/stats.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &statspb.PushRequest{Payload: &statspb.PushRequest_Json{Json: payload}}
+}
+
+func TestPush(t *testing.T) {
+	exp, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, Static(exp), func() int { return 1 << 20 })
+	ctx := metadata.AppendToOutgoingContext(context.Background(), LIBRDKAFKA_VERSION_METADATA, "2.3.0")
+
+	if _, err := client.Push(ctx, jsonRequest(t)); err != nil {
+		t.Fatal(err)
+	}
+	mfs, err := exp.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients := 0
+	for _, mf := range mfs {
+		if mf.GetName() == "librdkafka_exporter_client_restarts_total" {
+			clients = len(mf.Metric)
+		}
+	}
+	if clients != 1 {
+		t.Errorf("got %d clients, want 1", clients)
+	}
+
+	for _, req := range []*statspb.PushRequest{
+		{},
+		{Payload: &statspb.PushRequest_Json{Json: []byte(`{"name":`)}},
+		{Payload: &statspb.PushRequest_Json{Json: []byte(`{}`)}},
+	} {
+		if _, err := client.Push(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: got %v, want %v", req, err, codes.InvalidArgument)
+		}
+	}
+}
+
+// The max size is read on every request, so reloads apply to the running server.
+func TestPushMaxSize(t *testing.T) {
+	exp, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var maxSize atomic.Int64
+	maxSize.Store(1 << 20)
+	client := newTestClient(t, Static(exp), func() int { return int(maxSize.Load()) })
+	req := jsonRequest(t)
+
+	if _, err := client.Push(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	maxSize.Store(1024)
+	if _, err := client.Push(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got %v, want %v", err, codes.ResourceExhausted)
+	}
+
+	// Rejected requests don't end the stream.
+	stream, err := client.StreamStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Accepted != 0 || res.Rejected != 2 {
+		t.Errorf("got %d accepted, %d rejected, want 0 and 2", res.Accepted, res.Rejected)
+	}
+
+	maxSize.Store(1 << 20)
+	if _, err := client.Push(context.Background(), req); err != nil {
+		t.Errorf("after raising the max size: %v", err)
+	}
+}
+
+// The errors of the ExporterFunc, such as an unknown tenant, are returned to the client.
+func TestPushExporterError(t *testing.T) {
+	exporter := func(ctx context.Context) (*prom.PrometheusLibrdKafkaExporter, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		return nil, status.Errorf(codes.PermissionDenied, "tenant %v", md.Get("x-tenant"))
+	}
+	client := newTestClient(t, exporter, func() int { return 1 << 20 })
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+	if _, err := client.Push(ctx, jsonRequest(t)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v, want %v", err, codes.PermissionDenied)
+	}
+}
+
+// metric returns the metric of a family gathered from exp having all the given labels.
+func metric(t *testing.T, exp *prom.PrometheusLibrdKafkaExporter, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	mfs, err := exp.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range mf.Metric {
+			for name, value := range labels {
+				found := false
+				for _, l := range m.Label {
+					found = found || l.GetName() == name && l.GetValue() == value
+				}
+				if !found {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+// The typed Stats message is converted to the same metrics as the JSON stats.
+func TestPushStats(t *testing.T) {
+	exp, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, Static(exp), func() int { return 1 << 20 })
+	req := &statspb.PushRequest{Payload: &statspb.PushRequest_Stats{Stats: &statspb.Stats{
+		Name:     "app#consumer-1",
+		ClientId: "app",
+		Type:     "consumer",
+		Ts:       1_000_000,
+		Time:     1700000000,
+		MsgCnt:   7,
+		Rxmsgs:   42,
+		Brokers: map[string]*statspb.Broker{"kafka:9092/1": {
+			Name:     "kafka:9092/1",
+			Nodeid:   1,
+			Nodename: "kafka:9092",
+			Source:   "configured",
+			State:    "UP",
+			Rx:       12,
+			Rtt:      &statspb.Window{Min: 100, Max: 300, Avg: 200, Cnt: 3},
+		}},
+		Topics: map[string]*statspb.Topic{"orders": {
+			Topic: "orders",
+			Partitions: map[string]*statspb.Partition{"0": {
+				Partition:   0,
+				Broker:      1,
+				Leader:      1,
+				FetchState:  "active",
+				HiOffset:    100,
+				ConsumerLag: 5,
+			}},
+		}},
+		Cgrp: &statspb.ConsumerGroup{State: "up", JoinState: "steady", RebalanceCnt: 2},
+	}}}
+	if _, err := client.Push(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	clientLabels := map[string]string{"client_id": "app", "name": "app#consumer-1", "type": "consumer"}
+	broker := map[string]string{"client_id": "app", "broker": "kafka:9092/1", "nodeid": "1", "nodename": "kafka:9092", "source": "configured"}
+	partition := map[string]string{"client_id": "app", "topic": "orders", "partition": "0"}
+	for _, tc := range []struct {
+		name   string
+		labels map[string]string
+		value  float64
+	}{
+		{"librdkafka_msg_cnt", clientLabels, 7},
+		{"librdkafka_rxmsgs", clientLabels, 42},
+		{"librdkafka_brokers_rx", broker, 12},
+		{"librdkafka_brokers_rtt_avg", broker, 200},
+		{"librdkafka_brokers_rtt_cnt", broker, 3},
+		{"librdkafka_brokers_state", map[string]string{"broker": "kafka:9092/1", "state": "UP"}, 1},
+		{"librdkafka_topics_partitions_hi_offset", partition, 100},
+		{"librdkafka_topics_partitions_consumer_lag", partition, 5},
+		{"librdkafka_topics_partitions_fetch_state", map[string]string{"topic": "orders", "fetch_state": "active"}, 1},
+		{"librdkafka_consumergroups_rebalance_cnt", map[string]string{"client_id": "app"}, 2},
+	} {
+		m := metric(t, exp, tc.name, tc.labels)
+		if m == nil {
+			t.Errorf("%s%v: not exported", tc.name, tc.labels)
+			continue
+		}
+		v := m.GetGauge().GetValue() + m.GetCounter().GetValue()
+		if v != tc.value {
+			t.Errorf("%s%v: got %v, want %v", tc.name, tc.labels, v, tc.value)
+		}
+	}
+}
+
+// The transport rejects messages above its bounded limit, even when a reload raises the max size.
+func TestPushTransportLimit(t *testing.T) {
+	exp, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var maxSize atomic.Int64
+	maxSize.Store(1 << 20)
+	client := newTestClient(t, Static(exp), func() int { return int(maxSize.Load()) })
+
+	req := jsonRequest(t)
+	json := req.GetJson()
+	req.Payload = &statspb.PushRequest_Json{Json: append(json, bytes.Repeat([]byte(" "), 3<<20)...)}
+	maxSize.Store(8 << 20)
+	if _, err := client.Push(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got %v, want %v", err, codes.ResourceExhausted)
+	}
+	if got, want := maxRecvSize(1<<20), 2<<20; got != want {
+		t.Errorf("max recv size: got %d, want %d", got, want)
+	}
+}
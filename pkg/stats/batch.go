@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BatchItem is a stats object of a batch, or the reason it could not be decoded.
+type BatchItem struct {
+	Stats *Stats
+	Err   error
+}
+
+// DecodeBatch reads a batch of stats objects from r, either a JSON array or a stream of
+// JSON objects such as newline-delimited JSON. Objects that can't be decoded are reported
+// per item. A syntax error in a stream ends the batch with a last failed item.
+func DecodeBatch(r io.Reader) ([]BatchItem, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmpty
+		}
+		return nil, err
+	}
+
+	var items []BatchItem
+	if first == '[' {
+		var raws []json.RawMessage
+		if err := json.NewDecoder(br).Decode(&raws); err != nil {
+			return nil, fmt.Errorf("invalid stats batch: %w", err)
+		}
+		for _, raw := range raws {
+			items = append(items, decodeItem(raw))
+		}
+	} else {
+		dec := json.NewDecoder(br)
+		for {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				if !errors.Is(err, io.EOF) {
+					items = append(items, BatchItem{Err: fmt.Errorf("invalid stats payload: %w", err)})
+				}
+				break
+			}
+			items = append(items, decodeItem(raw))
+		}
+	}
+	if len(items) == 0 {
+		return nil, ErrEmpty
+	}
+	return items, nil
+}
+
+func decodeItem(raw json.RawMessage) BatchItem {
+	if string(raw) == "null" {
+		return BatchItem{Err: ErrEmpty}
+	}
+	var s Stats
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return BatchItem{Err: fmt.Errorf("invalid stats payload: %w", err)}
+	}
+	return BatchItem{Stats: &s}
+}
+
+// peekNonSpace skips the leading white space of r and returns the next byte without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.Discard(1)
+		default:
+			return b[0], nil
+		}
+	}
+}
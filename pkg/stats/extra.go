@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Extra holds the numeric fields of a stats object unknown to this package,
+// e.g. fields added by newer librdkafka versions.
+type Extra map[string]float64
+
+// decodeExtra decodes the numeric fields of data that are not part of the type of obj.
+func decodeExtra(data []byte, obj interface{}) (Extra, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	index := indexOf(reflect.TypeOf(obj).Elem())
+	var extra Extra
+	for name, value := range raw {
+		if _, ok := index[name]; ok || len(value) == 0 || !isNumber(value[0]) {
+			continue
+		}
+		var n float64
+		if err := json.Unmarshal(value, &n); err != nil {
+			continue
+		}
+		if extra == nil {
+			extra = make(Extra)
+		}
+		extra[name] = n
+	}
+	return extra, nil
+}
+
+func isNumber(c byte) bool {
+	return c == '-' || (c >= '0' && c <= '9')
+}
+
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	type plain Stats
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	var err error
+	s.Extra, err = decodeExtra(data, s)
+	return err
+}
+
+func (b *Broker) UnmarshalJSON(data []byte) error {
+	type plain Broker
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+	var err error
+	b.Extra, err = decodeExtra(data, b)
+	return err
+}
+
+func (t *Topic) UnmarshalJSON(data []byte) error {
+	type plain Topic
+	if err := json.Unmarshal(data, (*plain)(t)); err != nil {
+		return err
+	}
+	var err error
+	t.Extra, err = decodeExtra(data, t)
+	return err
+}
+
+func (p *Partition) UnmarshalJSON(data []byte) error {
+	type plain Partition
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	var err error
+	p.Extra, err = decodeExtra(data, p)
+	return err
+}
+
+func (c *ConsumerGroup) UnmarshalJSON(data []byte) error {
+	type plain ConsumerGroup
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	var err error
+	c.Extra, err = decodeExtra(data, c)
+	return err
+}
+
+func (e *EOS) UnmarshalJSON(data []byte) error {
+	type plain EOS
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	var err error
+	e.Extra, err = decodeExtra(data, e)
+	return err
+}
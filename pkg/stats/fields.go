@@ -0,0 +1,273 @@
+package stats
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is the kind of a stats object field.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindNumber       // integer, float or boolean (0 or 1)
+	KindString
+	KindWindow  // window stats
+	KindObject  // single object (cgrp, eos)
+	KindObjects // dict of objects (brokers, topics, partitions)
+	KindMap     // dict of numbers (req)
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindWindow:
+		return "window stats"
+	case KindObject:
+		return "object"
+	case KindObjects:
+		return "dict of objects"
+	case KindMap:
+		return "dict of numbers"
+	}
+	return "unknown"
+}
+
+type field struct {
+	index []int
+	kind  Kind
+}
+
+// fieldIndex maps the JSON name of a field to its struct index and kind.
+type fieldIndex map[string]field
+
+var (
+	fieldCache sync.Map // reflect.Type -> fieldIndex
+	windowType = reflect.TypeOf(Window{})
+	extraType  = reflect.TypeOf(Extra{})
+)
+
+func kindOf(t reflect.Type) Kind {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64, reflect.Bool:
+		return KindNumber
+	case reflect.String:
+		return KindString
+	case reflect.Pointer, reflect.Struct:
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == windowType {
+			return KindWindow
+		}
+		if t.Kind() == reflect.Struct {
+			return KindObject
+		}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Struct {
+			return KindObjects
+		}
+		if kindOf(t.Elem()) == KindNumber {
+			return KindMap
+		}
+	}
+	return KindUnknown
+}
+
+func indexOf(t reflect.Type) fieldIndex {
+	if idx, ok := fieldCache.Load(t); ok {
+		return idx.(fieldIndex)
+	}
+	idx := make(fieldIndex)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if kind := kindOf(f.Type); kind != KindUnknown {
+			idx[name] = field{index: f.Index, kind: kind}
+		}
+	}
+	fieldCache.Store(t, idx)
+	return idx
+}
+
+func structValue(obj interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+// extraOf returns the fields of a stats object unknown to this package.
+func extraOf(v reflect.Value) Extra {
+	if f := v.FieldByName("Extra"); f.IsValid() && f.Type() == extraType {
+		return f.Interface().(Extra)
+	}
+	return nil
+}
+
+// lookup returns the field of a stats object with the given JSON name.
+func lookup(obj interface{}, name string) (reflect.Value, Kind, bool) {
+	v, ok := structValue(obj)
+	if !ok {
+		return v, KindUnknown, false
+	}
+	f, ok := indexOf(v.Type())[name]
+	if !ok {
+		return v, KindUnknown, false
+	}
+	return v.FieldByIndex(f.index), f.kind, true
+}
+
+// Numbers returns the numeric fields of a stats object keyed by their JSON name,
+// including the unknown ones. Booleans are reported as 0 or 1.
+func Numbers(obj interface{}) map[string]float64 {
+	v, ok := structValue(obj)
+	if !ok {
+		return nil
+	}
+	numbers := make(map[string]float64)
+	for name, value := range extraOf(v) {
+		numbers[name] = value
+	}
+	for name, f := range indexOf(v.Type()) {
+		if f.kind == KindNumber {
+			numbers[name], _ = number(v.FieldByIndex(f.index))
+		}
+	}
+	return numbers
+}
+
+// Number returns the numeric field of a stats object with the given JSON name,
+// falling back to the fields unknown to this package.
+func Number(obj interface{}, name string) (float64, bool) {
+	f, kind, ok := lookup(obj, name)
+	if !ok {
+		if v, ok := structValue(obj); ok {
+			n, ok := extraOf(v)[name]
+			return n, ok
+		}
+		return 0, false
+	}
+	if kind != KindNumber {
+		return 0, false
+	}
+	return number(f)
+}
+
+func number(f reflect.Value) (float64, bool) {
+	switch f.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return float64(f.Int()), true
+	case reflect.Float64:
+		return f.Float(), true
+	case reflect.Bool:
+		if f.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Label returns the scalar field of a stats object with the given JSON name formatted as a label value,
+// or an empty string when the object has no such field.
+func Label(obj interface{}, name string) string {
+	f, kind, ok := lookup(obj, name)
+	if !ok {
+		if n, ok := Number(obj, name); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		return ""
+	}
+	if kind == KindString {
+		return f.String()
+	}
+	n, _ := number(f)
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// WindowOf returns the window stats of a stats object with the given JSON name, nil when missing.
+func WindowOf(obj interface{}, name string) *Window {
+	f, kind, ok := lookup(obj, name)
+	if !ok || kind != KindWindow {
+		return nil
+	}
+	return f.Interface().(*Window)
+}
+
+// Children returns the objects held by the field of a stats object with the given JSON name:
+// the object itself for a single object, the values sorted by key for a dict of objects.
+func Children(obj interface{}, name string) []interface{} {
+	f, kind, ok := lookup(obj, name)
+	if !ok {
+		return nil
+	}
+	switch kind {
+	case KindObject:
+		if f.Kind() == reflect.Pointer && f.IsNil() {
+			return nil
+		}
+		return []interface{}{f.Interface()}
+	case KindObjects:
+		keys := f.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		children := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			children = append(children, f.MapIndex(k).Interface())
+		}
+		return children
+	}
+	return nil
+}
+
+// Field describes the field of a stats object type with the given JSON name.
+// For objects and dicts of objects it also returns a zero value of the object type,
+// so nested fields can be described in turn.
+func Field(obj interface{}, name string) (Kind, interface{}) {
+	v, ok := structValue(obj)
+	if !ok {
+		return KindUnknown, nil
+	}
+	f, ok := indexOf(v.Type())[name]
+	if !ok {
+		return KindUnknown, nil
+	}
+	t := v.Type().FieldByIndex(f.index).Type
+	switch f.kind {
+	case KindObject:
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		return f.kind, reflect.New(t).Interface()
+	case KindObjects:
+		return f.kind, reflect.New(t.Elem()).Interface()
+	}
+	return f.kind, nil
+}
+
+// NumberMap returns the dict of numbers held by the field of a stats object with the given JSON name (req).
+func NumberMap(obj interface{}, name string) map[string]float64 {
+	f, kind, ok := lookup(obj, name)
+	if !ok || kind != KindMap {
+		return nil
+	}
+	numbers := make(map[string]float64, f.Len())
+	iter := f.MapRange()
+	for iter.Next() {
+		numbers[iter.Key().String()], _ = number(iter.Value())
+	}
+	return numbers
+}
@@ -0,0 +1,213 @@
+// Package stats decodes the librdkafka statistics JSON emitted through stats_cb.
+// See https://github.com/confluentinc/librdkafka/blob/master/STATISTICS.md
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Stats is the top-level librdkafka statistics object.
+type Stats struct {
+	Name             string            `json:"name"`
+	ClientID         string            `json:"client_id"`
+	Type             string            `json:"type"`
+	Ts               int64             `json:"ts"`
+	Time             int64             `json:"time"`
+	Age              int64             `json:"age"`
+	Replyq           int64             `json:"replyq"`
+	MsgCnt           int64             `json:"msg_cnt"`
+	MsgSize          int64             `json:"msg_size"`
+	MsgMax           int64             `json:"msg_max"`
+	MsgSizeMax       int64             `json:"msg_size_max"`
+	SimpleCnt        int64             `json:"simple_cnt"`
+	MetadataCacheCnt int64             `json:"metadata_cache_cnt"`
+	Brokers          map[string]Broker `json:"brokers"`
+	Topics           map[string]Topic  `json:"topics"`
+	Cgrp             *ConsumerGroup    `json:"cgrp"`
+	EOS              *EOS              `json:"eos"`
+	Tx               int64             `json:"tx"`
+	TxBytes          int64             `json:"tx_bytes"`
+	Rx               int64             `json:"rx"`
+	RxBytes          int64             `json:"rx_bytes"`
+	TxMsgs           int64             `json:"txmsgs"`
+	TxMsgBytes       int64             `json:"txmsg_bytes"`
+	RxMsgs           int64             `json:"rxmsgs"`
+	RxMsgBytes       int64             `json:"rxmsg_bytes"`
+	Extra            Extra             `json:"-"`
+}
+
+// Broker holds the per broker statistics.
+type Broker struct {
+	Name           string            `json:"name"`
+	NodeID         int64             `json:"nodeid"`
+	NodeName       string            `json:"nodename"`
+	Source         string            `json:"source"`
+	State          string            `json:"state"`
+	StateAge       int64             `json:"stateage"`
+	OutbufCnt      int64             `json:"outbuf_cnt"`
+	OutbufMsgCnt   int64             `json:"outbuf_msg_cnt"`
+	WaitrespCnt    int64             `json:"waitresp_cnt"`
+	WaitrespMsgCnt int64             `json:"waitresp_msg_cnt"`
+	Tx             int64             `json:"tx"`
+	TxBytes        int64             `json:"txbytes"`
+	TxErrs         int64             `json:"txerrs"`
+	TxRetries      int64             `json:"txretries"`
+	TxIdle         int64             `json:"txidle"`
+	ReqTimeouts    int64             `json:"req_timeouts"`
+	Rx             int64             `json:"rx"`
+	RxBytes        int64             `json:"rxbytes"`
+	RxErrs         int64             `json:"rxerrs"`
+	RxCorridErrs   int64             `json:"rxcorriderrs"`
+	RxPartial      int64             `json:"rxpartial"`
+	RxIdle         int64             `json:"rxidle"`
+	Req            map[string]int64  `json:"req"`
+	ZbufGrow       int64             `json:"zbuf_grow"`
+	BufGrow        int64             `json:"buf_grow"`
+	Wakeups        int64             `json:"wakeups"`
+	Connects       int64             `json:"connects"`
+	Disconnects    int64             `json:"disconnects"`
+	IntLatency     *Window           `json:"int_latency"`
+	OutbufLatency  *Window           `json:"outbuf_latency"`
+	Rtt            *Window           `json:"rtt"`
+	Throttle       *Window           `json:"throttle"`
+	Toppars        map[string]Toppar `json:"toppars"`
+	Extra          Extra             `json:"-"`
+}
+
+// Toppar is a partition handled by a broker.
+type Toppar struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+}
+
+// Window holds the rolling window statistics of a hdr histogram.
+type Window struct {
+	Min        int64 `json:"min"`
+	Max        int64 `json:"max"`
+	Avg        int64 `json:"avg"`
+	Sum        int64 `json:"sum"`
+	Stddev     int64 `json:"stddev"`
+	P50        int64 `json:"p50"`
+	P75        int64 `json:"p75"`
+	P90        int64 `json:"p90"`
+	P95        int64 `json:"p95"`
+	P99        int64 `json:"p99"`
+	P99_99     int64 `json:"p99_99"`
+	OutOfRange int64 `json:"outofrange"`
+	HdrSize    int64 `json:"hdrsize"`
+	Cnt        int64 `json:"cnt"`
+}
+
+// Topic holds the per topic statistics.
+type Topic struct {
+	Topic       string               `json:"topic"`
+	Age         int64                `json:"age"`
+	MetadataAge int64                `json:"metadata_age"`
+	BatchSize   *Window              `json:"batchsize"`
+	BatchCnt    *Window              `json:"batchcnt"`
+	Partitions  map[string]Partition `json:"partitions"`
+	Extra       Extra                `json:"-"`
+}
+
+// Partition holds the per partition statistics.
+type Partition struct {
+	Partition            int32  `json:"partition"`
+	Broker               int32  `json:"broker"`
+	Leader               int32  `json:"leader"`
+	Desired              bool   `json:"desired"`
+	Unknown              bool   `json:"unknown"`
+	MsgqCnt              int64  `json:"msgq_cnt"`
+	MsgqBytes            int64  `json:"msgq_bytes"`
+	XmitMsgqCnt          int64  `json:"xmit_msgq_cnt"`
+	XmitMsgqBytes        int64  `json:"xmit_msgq_bytes"`
+	FetchqCnt            int64  `json:"fetchq_cnt"`
+	FetchqSize           int64  `json:"fetchq_size"`
+	FetchState           string `json:"fetch_state"`
+	QueryOffset          int64  `json:"query_offset"`
+	NextOffset           int64  `json:"next_offset"`
+	AppOffset            int64  `json:"app_offset"`
+	StoredOffset         int64  `json:"stored_offset"`
+	StoredLeaderEpoch    int64  `json:"stored_leader_epoch"`
+	CommittedOffset      int64  `json:"committed_offset"`
+	CommittedLeaderEpoch int64  `json:"committed_leader_epoch"`
+	EOFOffset            int64  `json:"eof_offset"`
+	LoOffset             int64  `json:"lo_offset"`
+	HiOffset             int64  `json:"hi_offset"`
+	LsOffset             int64  `json:"ls_offset"`
+	ConsumerLag          int64  `json:"consumer_lag"`
+	ConsumerLagStored    int64  `json:"consumer_lag_stored"`
+	LeaderEpoch          int64  `json:"leader_epoch"`
+	TxMsgs               int64  `json:"txmsgs"`
+	TxBytes              int64  `json:"txbytes"`
+	RxMsgs               int64  `json:"rxmsgs"`
+	RxBytes              int64  `json:"rxbytes"`
+	Msgs                 int64  `json:"msgs"`
+	RxVerDrops           int64  `json:"rx_ver_drops"`
+	MsgsInflight         int64  `json:"msgs_inflight"`
+	NextAckSeq           int64  `json:"next_ack_seq"`
+	NextErrSeq           int64  `json:"next_err_seq"`
+	AckedMsgID           int64  `json:"acked_msgid"`
+	Extra                Extra  `json:"-"`
+}
+
+// ConsumerGroup holds the consumer group statistics.
+type ConsumerGroup struct {
+	State           string `json:"state"`
+	StateAge        int64  `json:"stateage"`
+	JoinState       string `json:"join_state"`
+	RebalanceAge    int64  `json:"rebalance_age"`
+	RebalanceCnt    int64  `json:"rebalance_cnt"`
+	RebalanceReason string `json:"rebalance_reason"`
+	AssignmentSize  int64  `json:"assignment_size"`
+	Extra           Extra  `json:"-"`
+}
+
+// EOS holds the idempotent and transactional producer statistics.
+type EOS struct {
+	IdempState    string `json:"idemp_state"`
+	IdempStateAge int64  `json:"idemp_stateage"`
+	TxnState      string `json:"txn_state"`
+	TxnStateAge   int64  `json:"txn_stateage"`
+	TxnMayEnq     bool   `json:"txn_may_enq"`
+	ProducerID    int64  `json:"producer_id"`
+	ProducerEpoch int64  `json:"producer_epoch"`
+	EpochCnt      int64  `json:"epoch_cnt"`
+	Extra         Extra  `json:"-"`
+}
+
+// ErrEmpty is returned when there is no stats object to decode.
+var ErrEmpty = errors.New("empty stats payload")
+
+// ErrNoClient is returned for stats that don't identify the client instance that emitted them.
+var ErrNoClient = errors.New("stats payload without name or type")
+
+// Validate checks that the stats identify their client instance, as every stats_cb payload does.
+func (s *Stats) Validate() error {
+	if s.Name == "" || s.Type == "" {
+		return ErrNoClient
+	}
+	return nil
+}
+
+// Decode reads a single stats object from r.
+// Unknown fields are ignored and missing fields are left to their zero value,
+// the exporter rejects the stats without name or type.
+func Decode(r io.Reader) (*Stats, error) {
+	var s Stats
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmpty
+		}
+		return nil, fmt.Errorf("invalid stats payload: %w", err)
+	}
+	return &s, nil
+}
+
+// Unmarshal decodes a single stats object from data.
+func Unmarshal(data []byte) (*Stats, error) {
+	return Decode(bytes.NewReader(data))
+}
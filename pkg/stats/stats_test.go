@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFixture(t *testing.T) {
+	f, err := os.Open("../../cmd/stats.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "rdkafka#producer-1" || s.ClientID != "rdkafka" || s.Type != "producer" {
+		t.Errorf("got client %s/%s/%s", s.ClientID, s.Name, s.Type)
+	}
+	if rtt := s.Brokers["localhost:9092/2"].Rtt; rtt == nil || rtt.Cnt == 0 {
+		t.Errorf("missing rtt window: %+v", rtt)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	for _, tc := range []struct {
+		payload string
+		err     error
+	}{
+		{"", ErrEmpty},
+		{"   ", ErrEmpty},
+	} {
+		_, err := Decode(strings.NewReader(tc.payload))
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%q: got error %v, want %v", tc.payload, err, tc.err)
+		}
+	}
+	if _, err := Decode(strings.NewReader(`{"name":`)); err == nil {
+		t.Error("expected an error for a truncated payload")
+	}
+	if _, err := Decode(strings.NewReader(`{"name":"c#consumer-1","type":"consumer"}`)); err != nil {
+		t.Errorf("minimal stats rejected: %v", err)
+	}
+}
+
+// Decode leaves the stats without name or type to Validate, called by the exporter.
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		payload string
+		err     error
+	}{
+		{"{}", ErrNoClient},
+		{"null", ErrNoClient},
+		{`{"name":"rdkafka#producer-1"}`, ErrNoClient},
+		{`{"type":"producer"}`, ErrNoClient},
+		{`{"name":"c#consumer-1","type":"consumer"}`, nil},
+	} {
+		s, err := Decode(strings.NewReader(tc.payload))
+		if err != nil {
+			t.Errorf("%q: %v", tc.payload, err)
+			continue
+		}
+		if err := s.Validate(); !errors.Is(err, tc.err) {
+			t.Errorf("%q: got error %v, want %v", tc.payload, err, tc.err)
+		}
+	}
+}
+
+func TestDecodeBatch(t *testing.T) {
+	for _, payload := range []string{
+		`[{"name":"a","type":"producer","ts":1}, {}, null, {"name":"b","type":"consumer"}]`,
+		"{\"name\":\"a\",\"type\":\"producer\",\"ts\":1}\n{}\nnull\n{\"name\":\"b\",\"type\":\"consumer\"}\n",
+	} {
+		items, err := DecodeBatch(strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 4 {
+			t.Fatalf("got %d items, want 4", len(items))
+		}
+		if items[0].Err != nil || items[0].Stats.Name != "a" || items[3].Err != nil || items[3].Stats.Name != "b" {
+			t.Errorf("valid items: %+v, %+v", items[0], items[3])
+		}
+		if items[1].Err != nil || !errors.Is(items[1].Stats.Validate(), ErrNoClient) {
+			t.Errorf("{}: got %+v, want stats without client", items[1])
+		}
+		if !errors.Is(items[2].Err, ErrEmpty) {
+			t.Errorf("null: got %v, want %v", items[2].Err, ErrEmpty)
+		}
+	}
+	if _, err := DecodeBatch(strings.NewReader(" \n")); !errors.Is(err, ErrEmpty) {
+		t.Errorf("empty batch: got %v, want %v", err, ErrEmpty)
+	}
+}
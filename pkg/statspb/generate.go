@@ -0,0 +1,5 @@
+// Package statspb holds the protobuf messages and the gRPC StatsService generated from
+// proto/librdkafka/stats/v1/stats.proto.
+package statspb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=mcolomerc/librdkafka-prometheus-exporter --go-grpc_out=../.. --go-grpc_opt=module=mcolomerc/librdkafka-prometheus-exporter librdkafka/stats/v1/stats.proto
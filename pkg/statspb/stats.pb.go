@@ -0,0 +1,2229 @@
+// librdkafka statistics, mirroring the JSON emitted through stats_cb.
+// See https://github.com/confluentinc/librdkafka/blob/master/STATISTICS.md
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: librdkafka/stats/v1/stats.proto
+
+package statspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PushRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Payload:
+	//	*PushRequest_Stats
+	//	*PushRequest_Json
+	Payload isPushRequest_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *PushRequest) Reset() {
+	*x = PushRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushRequest) ProtoMessage() {}
+
+func (x *PushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushRequest.ProtoReflect.Descriptor instead.
+func (*PushRequest) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *PushRequest) GetPayload() isPushRequest_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *PushRequest) GetStats() *Stats {
+	if x, ok := x.GetPayload().(*PushRequest_Stats); ok {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *PushRequest) GetJson() []byte {
+	if x, ok := x.GetPayload().(*PushRequest_Json); ok {
+		return x.Json
+	}
+	return nil
+}
+
+type isPushRequest_Payload interface {
+	isPushRequest_Payload()
+}
+
+type PushRequest_Stats struct {
+	Stats *Stats `protobuf:"bytes,1,opt,name=stats,proto3,oneof"`
+}
+
+type PushRequest_Json struct {
+	// json is the stats_cb JSON as is, for clients that can't convert it.
+	Json []byte `protobuf:"bytes,2,opt,name=json,proto3,oneof"`
+}
+
+func (*PushRequest_Stats) isPushRequest_Payload() {}
+
+func (*PushRequest_Json) isPushRequest_Payload() {}
+
+type PushResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PushResponse) Reset() {
+	*x = PushResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushResponse) ProtoMessage() {}
+
+func (x *PushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushResponse.ProtoReflect.Descriptor instead.
+func (*PushResponse) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{1}
+}
+
+type StreamStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accepted int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected int64 `protobuf:"varint,2,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	// errors holds the error of every rejected request, in the stream order.
+	Errors []string `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *StreamStatsResponse) Reset() {
+	*x = StreamStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStatsResponse) ProtoMessage() {}
+
+func (x *StreamStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStatsResponse.ProtoReflect.Descriptor instead.
+func (*StreamStatsResponse) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamStatsResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *StreamStatsResponse) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *StreamStatsResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// Stats is the top-level librdkafka statistics object.
+type Stats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name             string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ClientId         string             `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Type             string             `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Ts               int64              `protobuf:"varint,4,opt,name=ts,proto3" json:"ts,omitempty"`
+	Time             int64              `protobuf:"varint,5,opt,name=time,proto3" json:"time,omitempty"`
+	Age              int64              `protobuf:"varint,6,opt,name=age,proto3" json:"age,omitempty"`
+	Replyq           int64              `protobuf:"varint,7,opt,name=replyq,proto3" json:"replyq,omitempty"`
+	MsgCnt           int64              `protobuf:"varint,8,opt,name=msg_cnt,json=msgCnt,proto3" json:"msg_cnt,omitempty"`
+	MsgSize          int64              `protobuf:"varint,9,opt,name=msg_size,json=msgSize,proto3" json:"msg_size,omitempty"`
+	MsgMax           int64              `protobuf:"varint,10,opt,name=msg_max,json=msgMax,proto3" json:"msg_max,omitempty"`
+	MsgSizeMax       int64              `protobuf:"varint,11,opt,name=msg_size_max,json=msgSizeMax,proto3" json:"msg_size_max,omitempty"`
+	SimpleCnt        int64              `protobuf:"varint,12,opt,name=simple_cnt,json=simpleCnt,proto3" json:"simple_cnt,omitempty"`
+	MetadataCacheCnt int64              `protobuf:"varint,13,opt,name=metadata_cache_cnt,json=metadataCacheCnt,proto3" json:"metadata_cache_cnt,omitempty"`
+	Brokers          map[string]*Broker `protobuf:"bytes,14,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Topics           map[string]*Topic  `protobuf:"bytes,15,rep,name=topics,proto3" json:"topics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Cgrp             *ConsumerGroup     `protobuf:"bytes,16,opt,name=cgrp,proto3" json:"cgrp,omitempty"`
+	Eos              *EOS               `protobuf:"bytes,17,opt,name=eos,proto3" json:"eos,omitempty"`
+	Tx               int64              `protobuf:"varint,18,opt,name=tx,proto3" json:"tx,omitempty"`
+	TxBytes          int64              `protobuf:"varint,19,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	Rx               int64              `protobuf:"varint,20,opt,name=rx,proto3" json:"rx,omitempty"`
+	RxBytes          int64              `protobuf:"varint,21,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	Txmsgs           int64              `protobuf:"varint,22,opt,name=txmsgs,proto3" json:"txmsgs,omitempty"`
+	TxmsgBytes       int64              `protobuf:"varint,23,opt,name=txmsg_bytes,json=txmsgBytes,proto3" json:"txmsg_bytes,omitempty"`
+	Rxmsgs           int64              `protobuf:"varint,24,opt,name=rxmsgs,proto3" json:"rxmsgs,omitempty"`
+	RxmsgBytes       int64              `protobuf:"varint,25,opt,name=rxmsg_bytes,json=rxmsgBytes,proto3" json:"rxmsg_bytes,omitempty"`
+	// extra holds the numeric fields unknown to this schema.
+	Extra map[string]float64 `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Stats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stats) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *Stats) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Stats) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+func (x *Stats) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Stats) GetAge() int64 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Stats) GetReplyq() int64 {
+	if x != nil {
+		return x.Replyq
+	}
+	return 0
+}
+
+func (x *Stats) GetMsgCnt() int64 {
+	if x != nil {
+		return x.MsgCnt
+	}
+	return 0
+}
+
+func (x *Stats) GetMsgSize() int64 {
+	if x != nil {
+		return x.MsgSize
+	}
+	return 0
+}
+
+func (x *Stats) GetMsgMax() int64 {
+	if x != nil {
+		return x.MsgMax
+	}
+	return 0
+}
+
+func (x *Stats) GetMsgSizeMax() int64 {
+	if x != nil {
+		return x.MsgSizeMax
+	}
+	return 0
+}
+
+func (x *Stats) GetSimpleCnt() int64 {
+	if x != nil {
+		return x.SimpleCnt
+	}
+	return 0
+}
+
+func (x *Stats) GetMetadataCacheCnt() int64 {
+	if x != nil {
+		return x.MetadataCacheCnt
+	}
+	return 0
+}
+
+func (x *Stats) GetBrokers() map[string]*Broker {
+	if x != nil {
+		return x.Brokers
+	}
+	return nil
+}
+
+func (x *Stats) GetTopics() map[string]*Topic {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *Stats) GetCgrp() *ConsumerGroup {
+	if x != nil {
+		return x.Cgrp
+	}
+	return nil
+}
+
+func (x *Stats) GetEos() *EOS {
+	if x != nil {
+		return x.Eos
+	}
+	return nil
+}
+
+func (x *Stats) GetTx() int64 {
+	if x != nil {
+		return x.Tx
+	}
+	return 0
+}
+
+func (x *Stats) GetTxBytes() int64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetRx() int64 {
+	if x != nil {
+		return x.Rx
+	}
+	return 0
+}
+
+func (x *Stats) GetRxBytes() int64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetTxmsgs() int64 {
+	if x != nil {
+		return x.Txmsgs
+	}
+	return 0
+}
+
+func (x *Stats) GetTxmsgBytes() int64 {
+	if x != nil {
+		return x.TxmsgBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetRxmsgs() int64 {
+	if x != nil {
+		return x.Rxmsgs
+	}
+	return 0
+}
+
+func (x *Stats) GetRxmsgBytes() int64 {
+	if x != nil {
+		return x.RxmsgBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+type Broker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Nodeid         int64              `protobuf:"varint,2,opt,name=nodeid,proto3" json:"nodeid,omitempty"`
+	Nodename       string             `protobuf:"bytes,3,opt,name=nodename,proto3" json:"nodename,omitempty"`
+	Source         string             `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	State          string             `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Stateage       int64              `protobuf:"varint,6,opt,name=stateage,proto3" json:"stateage,omitempty"`
+	OutbufCnt      int64              `protobuf:"varint,7,opt,name=outbuf_cnt,json=outbufCnt,proto3" json:"outbuf_cnt,omitempty"`
+	OutbufMsgCnt   int64              `protobuf:"varint,8,opt,name=outbuf_msg_cnt,json=outbufMsgCnt,proto3" json:"outbuf_msg_cnt,omitempty"`
+	WaitrespCnt    int64              `protobuf:"varint,9,opt,name=waitresp_cnt,json=waitrespCnt,proto3" json:"waitresp_cnt,omitempty"`
+	WaitrespMsgCnt int64              `protobuf:"varint,10,opt,name=waitresp_msg_cnt,json=waitrespMsgCnt,proto3" json:"waitresp_msg_cnt,omitempty"`
+	Tx             int64              `protobuf:"varint,11,opt,name=tx,proto3" json:"tx,omitempty"`
+	Txbytes        int64              `protobuf:"varint,12,opt,name=txbytes,proto3" json:"txbytes,omitempty"`
+	Txerrs         int64              `protobuf:"varint,13,opt,name=txerrs,proto3" json:"txerrs,omitempty"`
+	Txretries      int64              `protobuf:"varint,14,opt,name=txretries,proto3" json:"txretries,omitempty"`
+	Txidle         int64              `protobuf:"varint,15,opt,name=txidle,proto3" json:"txidle,omitempty"`
+	ReqTimeouts    int64              `protobuf:"varint,16,opt,name=req_timeouts,json=reqTimeouts,proto3" json:"req_timeouts,omitempty"`
+	Rx             int64              `protobuf:"varint,17,opt,name=rx,proto3" json:"rx,omitempty"`
+	Rxbytes        int64              `protobuf:"varint,18,opt,name=rxbytes,proto3" json:"rxbytes,omitempty"`
+	Rxerrs         int64              `protobuf:"varint,19,opt,name=rxerrs,proto3" json:"rxerrs,omitempty"`
+	Rxcorriderrs   int64              `protobuf:"varint,20,opt,name=rxcorriderrs,proto3" json:"rxcorriderrs,omitempty"`
+	Rxpartial      int64              `protobuf:"varint,21,opt,name=rxpartial,proto3" json:"rxpartial,omitempty"`
+	Rxidle         int64              `protobuf:"varint,22,opt,name=rxidle,proto3" json:"rxidle,omitempty"`
+	Req            map[string]int64   `protobuf:"bytes,23,rep,name=req,proto3" json:"req,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ZbufGrow       int64              `protobuf:"varint,24,opt,name=zbuf_grow,json=zbufGrow,proto3" json:"zbuf_grow,omitempty"`
+	BufGrow        int64              `protobuf:"varint,25,opt,name=buf_grow,json=bufGrow,proto3" json:"buf_grow,omitempty"`
+	Wakeups        int64              `protobuf:"varint,26,opt,name=wakeups,proto3" json:"wakeups,omitempty"`
+	Connects       int64              `protobuf:"varint,27,opt,name=connects,proto3" json:"connects,omitempty"`
+	Disconnects    int64              `protobuf:"varint,28,opt,name=disconnects,proto3" json:"disconnects,omitempty"`
+	IntLatency     *Window            `protobuf:"bytes,29,opt,name=int_latency,json=intLatency,proto3" json:"int_latency,omitempty"`
+	OutbufLatency  *Window            `protobuf:"bytes,30,opt,name=outbuf_latency,json=outbufLatency,proto3" json:"outbuf_latency,omitempty"`
+	Rtt            *Window            `protobuf:"bytes,31,opt,name=rtt,proto3" json:"rtt,omitempty"`
+	Throttle       *Window            `protobuf:"bytes,32,opt,name=throttle,proto3" json:"throttle,omitempty"`
+	Toppars        map[string]*Toppar `protobuf:"bytes,33,rep,name=toppars,proto3" json:"toppars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Extra          map[string]float64 `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Broker) Reset() {
+	*x = Broker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Broker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Broker) ProtoMessage() {}
+
+func (x *Broker) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Broker.ProtoReflect.Descriptor instead.
+func (*Broker) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Broker) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Broker) GetNodeid() int64 {
+	if x != nil {
+		return x.Nodeid
+	}
+	return 0
+}
+
+func (x *Broker) GetNodename() string {
+	if x != nil {
+		return x.Nodename
+	}
+	return ""
+}
+
+func (x *Broker) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Broker) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Broker) GetStateage() int64 {
+	if x != nil {
+		return x.Stateage
+	}
+	return 0
+}
+
+func (x *Broker) GetOutbufCnt() int64 {
+	if x != nil {
+		return x.OutbufCnt
+	}
+	return 0
+}
+
+func (x *Broker) GetOutbufMsgCnt() int64 {
+	if x != nil {
+		return x.OutbufMsgCnt
+	}
+	return 0
+}
+
+func (x *Broker) GetWaitrespCnt() int64 {
+	if x != nil {
+		return x.WaitrespCnt
+	}
+	return 0
+}
+
+func (x *Broker) GetWaitrespMsgCnt() int64 {
+	if x != nil {
+		return x.WaitrespMsgCnt
+	}
+	return 0
+}
+
+func (x *Broker) GetTx() int64 {
+	if x != nil {
+		return x.Tx
+	}
+	return 0
+}
+
+func (x *Broker) GetTxbytes() int64 {
+	if x != nil {
+		return x.Txbytes
+	}
+	return 0
+}
+
+func (x *Broker) GetTxerrs() int64 {
+	if x != nil {
+		return x.Txerrs
+	}
+	return 0
+}
+
+func (x *Broker) GetTxretries() int64 {
+	if x != nil {
+		return x.Txretries
+	}
+	return 0
+}
+
+func (x *Broker) GetTxidle() int64 {
+	if x != nil {
+		return x.Txidle
+	}
+	return 0
+}
+
+func (x *Broker) GetReqTimeouts() int64 {
+	if x != nil {
+		return x.ReqTimeouts
+	}
+	return 0
+}
+
+func (x *Broker) GetRx() int64 {
+	if x != nil {
+		return x.Rx
+	}
+	return 0
+}
+
+func (x *Broker) GetRxbytes() int64 {
+	if x != nil {
+		return x.Rxbytes
+	}
+	return 0
+}
+
+func (x *Broker) GetRxerrs() int64 {
+	if x != nil {
+		return x.Rxerrs
+	}
+	return 0
+}
+
+func (x *Broker) GetRxcorriderrs() int64 {
+	if x != nil {
+		return x.Rxcorriderrs
+	}
+	return 0
+}
+
+func (x *Broker) GetRxpartial() int64 {
+	if x != nil {
+		return x.Rxpartial
+	}
+	return 0
+}
+
+func (x *Broker) GetRxidle() int64 {
+	if x != nil {
+		return x.Rxidle
+	}
+	return 0
+}
+
+func (x *Broker) GetReq() map[string]int64 {
+	if x != nil {
+		return x.Req
+	}
+	return nil
+}
+
+func (x *Broker) GetZbufGrow() int64 {
+	if x != nil {
+		return x.ZbufGrow
+	}
+	return 0
+}
+
+func (x *Broker) GetBufGrow() int64 {
+	if x != nil {
+		return x.BufGrow
+	}
+	return 0
+}
+
+func (x *Broker) GetWakeups() int64 {
+	if x != nil {
+		return x.Wakeups
+	}
+	return 0
+}
+
+func (x *Broker) GetConnects() int64 {
+	if x != nil {
+		return x.Connects
+	}
+	return 0
+}
+
+func (x *Broker) GetDisconnects() int64 {
+	if x != nil {
+		return x.Disconnects
+	}
+	return 0
+}
+
+func (x *Broker) GetIntLatency() *Window {
+	if x != nil {
+		return x.IntLatency
+	}
+	return nil
+}
+
+func (x *Broker) GetOutbufLatency() *Window {
+	if x != nil {
+		return x.OutbufLatency
+	}
+	return nil
+}
+
+func (x *Broker) GetRtt() *Window {
+	if x != nil {
+		return x.Rtt
+	}
+	return nil
+}
+
+func (x *Broker) GetThrottle() *Window {
+	if x != nil {
+		return x.Throttle
+	}
+	return nil
+}
+
+func (x *Broker) GetToppars() map[string]*Toppar {
+	if x != nil {
+		return x.Toppars
+	}
+	return nil
+}
+
+func (x *Broker) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+type Toppar struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic     string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition int32  `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+}
+
+func (x *Toppar) Reset() {
+	*x = Toppar{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Toppar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Toppar) ProtoMessage() {}
+
+func (x *Toppar) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Toppar.ProtoReflect.Descriptor instead.
+func (*Toppar) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Toppar) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Toppar) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+// Window holds the rolling window statistics of a hdr histogram.
+type Window struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min        int64 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max        int64 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	Avg        int64 `protobuf:"varint,3,opt,name=avg,proto3" json:"avg,omitempty"`
+	Sum        int64 `protobuf:"varint,4,opt,name=sum,proto3" json:"sum,omitempty"`
+	Stddev     int64 `protobuf:"varint,5,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	P50        int64 `protobuf:"varint,6,opt,name=p50,proto3" json:"p50,omitempty"`
+	P75        int64 `protobuf:"varint,7,opt,name=p75,proto3" json:"p75,omitempty"`
+	P90        int64 `protobuf:"varint,8,opt,name=p90,proto3" json:"p90,omitempty"`
+	P95        int64 `protobuf:"varint,9,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99        int64 `protobuf:"varint,10,opt,name=p99,proto3" json:"p99,omitempty"`
+	P99_99     int64 `protobuf:"varint,11,opt,name=p99_99,json=p9999,proto3" json:"p99_99,omitempty"`
+	Outofrange int64 `protobuf:"varint,12,opt,name=outofrange,proto3" json:"outofrange,omitempty"`
+	Hdrsize    int64 `protobuf:"varint,13,opt,name=hdrsize,proto3" json:"hdrsize,omitempty"`
+	Cnt        int64 `protobuf:"varint,14,opt,name=cnt,proto3" json:"cnt,omitempty"`
+}
+
+func (x *Window) Reset() {
+	*x = Window{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Window) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Window) ProtoMessage() {}
+
+func (x *Window) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Window.ProtoReflect.Descriptor instead.
+func (*Window) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Window) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Window) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Window) GetAvg() int64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *Window) GetSum() int64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Window) GetStddev() int64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *Window) GetP50() int64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
+}
+
+func (x *Window) GetP75() int64 {
+	if x != nil {
+		return x.P75
+	}
+	return 0
+}
+
+func (x *Window) GetP90() int64 {
+	if x != nil {
+		return x.P90
+	}
+	return 0
+}
+
+func (x *Window) GetP95() int64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+func (x *Window) GetP99() int64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+func (x *Window) GetP99_99() int64 {
+	if x != nil {
+		return x.P99_99
+	}
+	return 0
+}
+
+func (x *Window) GetOutofrange() int64 {
+	if x != nil {
+		return x.Outofrange
+	}
+	return 0
+}
+
+func (x *Window) GetHdrsize() int64 {
+	if x != nil {
+		return x.Hdrsize
+	}
+	return 0
+}
+
+func (x *Window) GetCnt() int64 {
+	if x != nil {
+		return x.Cnt
+	}
+	return 0
+}
+
+type Topic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic       string                `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Age         int64                 `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+	MetadataAge int64                 `protobuf:"varint,3,opt,name=metadata_age,json=metadataAge,proto3" json:"metadata_age,omitempty"`
+	Batchsize   *Window               `protobuf:"bytes,4,opt,name=batchsize,proto3" json:"batchsize,omitempty"`
+	Batchcnt    *Window               `protobuf:"bytes,5,opt,name=batchcnt,proto3" json:"batchcnt,omitempty"`
+	Partitions  map[string]*Partition `protobuf:"bytes,6,rep,name=partitions,proto3" json:"partitions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Extra       map[string]float64    `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Topic) Reset() {
+	*x = Topic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Topic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Topic) ProtoMessage() {}
+
+func (x *Topic) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Topic.ProtoReflect.Descriptor instead.
+func (*Topic) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Topic) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Topic) GetAge() int64 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Topic) GetMetadataAge() int64 {
+	if x != nil {
+		return x.MetadataAge
+	}
+	return 0
+}
+
+func (x *Topic) GetBatchsize() *Window {
+	if x != nil {
+		return x.Batchsize
+	}
+	return nil
+}
+
+func (x *Topic) GetBatchcnt() *Window {
+	if x != nil {
+		return x.Batchcnt
+	}
+	return nil
+}
+
+func (x *Topic) GetPartitions() map[string]*Partition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+func (x *Topic) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+type Partition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Partition            int32              `protobuf:"varint,1,opt,name=partition,proto3" json:"partition,omitempty"`
+	Broker               int32              `protobuf:"varint,2,opt,name=broker,proto3" json:"broker,omitempty"`
+	Leader               int32              `protobuf:"varint,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	Desired              bool               `protobuf:"varint,4,opt,name=desired,proto3" json:"desired,omitempty"`
+	Unknown              bool               `protobuf:"varint,5,opt,name=unknown,proto3" json:"unknown,omitempty"`
+	MsgqCnt              int64              `protobuf:"varint,6,opt,name=msgq_cnt,json=msgqCnt,proto3" json:"msgq_cnt,omitempty"`
+	MsgqBytes            int64              `protobuf:"varint,7,opt,name=msgq_bytes,json=msgqBytes,proto3" json:"msgq_bytes,omitempty"`
+	XmitMsgqCnt          int64              `protobuf:"varint,8,opt,name=xmit_msgq_cnt,json=xmitMsgqCnt,proto3" json:"xmit_msgq_cnt,omitempty"`
+	XmitMsgqBytes        int64              `protobuf:"varint,9,opt,name=xmit_msgq_bytes,json=xmitMsgqBytes,proto3" json:"xmit_msgq_bytes,omitempty"`
+	FetchqCnt            int64              `protobuf:"varint,10,opt,name=fetchq_cnt,json=fetchqCnt,proto3" json:"fetchq_cnt,omitempty"`
+	FetchqSize           int64              `protobuf:"varint,11,opt,name=fetchq_size,json=fetchqSize,proto3" json:"fetchq_size,omitempty"`
+	FetchState           string             `protobuf:"bytes,12,opt,name=fetch_state,json=fetchState,proto3" json:"fetch_state,omitempty"`
+	QueryOffset          int64              `protobuf:"varint,13,opt,name=query_offset,json=queryOffset,proto3" json:"query_offset,omitempty"`
+	NextOffset           int64              `protobuf:"varint,14,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	AppOffset            int64              `protobuf:"varint,15,opt,name=app_offset,json=appOffset,proto3" json:"app_offset,omitempty"`
+	StoredOffset         int64              `protobuf:"varint,16,opt,name=stored_offset,json=storedOffset,proto3" json:"stored_offset,omitempty"`
+	StoredLeaderEpoch    int64              `protobuf:"varint,17,opt,name=stored_leader_epoch,json=storedLeaderEpoch,proto3" json:"stored_leader_epoch,omitempty"`
+	CommittedOffset      int64              `protobuf:"varint,18,opt,name=committed_offset,json=committedOffset,proto3" json:"committed_offset,omitempty"`
+	CommittedLeaderEpoch int64              `protobuf:"varint,19,opt,name=committed_leader_epoch,json=committedLeaderEpoch,proto3" json:"committed_leader_epoch,omitempty"`
+	EofOffset            int64              `protobuf:"varint,20,opt,name=eof_offset,json=eofOffset,proto3" json:"eof_offset,omitempty"`
+	LoOffset             int64              `protobuf:"varint,21,opt,name=lo_offset,json=loOffset,proto3" json:"lo_offset,omitempty"`
+	HiOffset             int64              `protobuf:"varint,22,opt,name=hi_offset,json=hiOffset,proto3" json:"hi_offset,omitempty"`
+	LsOffset             int64              `protobuf:"varint,23,opt,name=ls_offset,json=lsOffset,proto3" json:"ls_offset,omitempty"`
+	ConsumerLag          int64              `protobuf:"varint,24,opt,name=consumer_lag,json=consumerLag,proto3" json:"consumer_lag,omitempty"`
+	ConsumerLagStored    int64              `protobuf:"varint,25,opt,name=consumer_lag_stored,json=consumerLagStored,proto3" json:"consumer_lag_stored,omitempty"`
+	LeaderEpoch          int64              `protobuf:"varint,26,opt,name=leader_epoch,json=leaderEpoch,proto3" json:"leader_epoch,omitempty"`
+	Txmsgs               int64              `protobuf:"varint,27,opt,name=txmsgs,proto3" json:"txmsgs,omitempty"`
+	Txbytes              int64              `protobuf:"varint,28,opt,name=txbytes,proto3" json:"txbytes,omitempty"`
+	Rxmsgs               int64              `protobuf:"varint,29,opt,name=rxmsgs,proto3" json:"rxmsgs,omitempty"`
+	Rxbytes              int64              `protobuf:"varint,30,opt,name=rxbytes,proto3" json:"rxbytes,omitempty"`
+	Msgs                 int64              `protobuf:"varint,31,opt,name=msgs,proto3" json:"msgs,omitempty"`
+	RxVerDrops           int64              `protobuf:"varint,32,opt,name=rx_ver_drops,json=rxVerDrops,proto3" json:"rx_ver_drops,omitempty"`
+	MsgsInflight         int64              `protobuf:"varint,33,opt,name=msgs_inflight,json=msgsInflight,proto3" json:"msgs_inflight,omitempty"`
+	NextAckSeq           int64              `protobuf:"varint,34,opt,name=next_ack_seq,json=nextAckSeq,proto3" json:"next_ack_seq,omitempty"`
+	NextErrSeq           int64              `protobuf:"varint,35,opt,name=next_err_seq,json=nextErrSeq,proto3" json:"next_err_seq,omitempty"`
+	AckedMsgid           int64              `protobuf:"varint,36,opt,name=acked_msgid,json=ackedMsgid,proto3" json:"acked_msgid,omitempty"`
+	Extra                map[string]float64 `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Partition) Reset() {
+	*x = Partition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Partition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Partition) ProtoMessage() {}
+
+func (x *Partition) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Partition.ProtoReflect.Descriptor instead.
+func (*Partition) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Partition) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *Partition) GetBroker() int32 {
+	if x != nil {
+		return x.Broker
+	}
+	return 0
+}
+
+func (x *Partition) GetLeader() int32 {
+	if x != nil {
+		return x.Leader
+	}
+	return 0
+}
+
+func (x *Partition) GetDesired() bool {
+	if x != nil {
+		return x.Desired
+	}
+	return false
+}
+
+func (x *Partition) GetUnknown() bool {
+	if x != nil {
+		return x.Unknown
+	}
+	return false
+}
+
+func (x *Partition) GetMsgqCnt() int64 {
+	if x != nil {
+		return x.MsgqCnt
+	}
+	return 0
+}
+
+func (x *Partition) GetMsgqBytes() int64 {
+	if x != nil {
+		return x.MsgqBytes
+	}
+	return 0
+}
+
+func (x *Partition) GetXmitMsgqCnt() int64 {
+	if x != nil {
+		return x.XmitMsgqCnt
+	}
+	return 0
+}
+
+func (x *Partition) GetXmitMsgqBytes() int64 {
+	if x != nil {
+		return x.XmitMsgqBytes
+	}
+	return 0
+}
+
+func (x *Partition) GetFetchqCnt() int64 {
+	if x != nil {
+		return x.FetchqCnt
+	}
+	return 0
+}
+
+func (x *Partition) GetFetchqSize() int64 {
+	if x != nil {
+		return x.FetchqSize
+	}
+	return 0
+}
+
+func (x *Partition) GetFetchState() string {
+	if x != nil {
+		return x.FetchState
+	}
+	return ""
+}
+
+func (x *Partition) GetQueryOffset() int64 {
+	if x != nil {
+		return x.QueryOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetNextOffset() int64 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetAppOffset() int64 {
+	if x != nil {
+		return x.AppOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetStoredOffset() int64 {
+	if x != nil {
+		return x.StoredOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetStoredLeaderEpoch() int64 {
+	if x != nil {
+		return x.StoredLeaderEpoch
+	}
+	return 0
+}
+
+func (x *Partition) GetCommittedOffset() int64 {
+	if x != nil {
+		return x.CommittedOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetCommittedLeaderEpoch() int64 {
+	if x != nil {
+		return x.CommittedLeaderEpoch
+	}
+	return 0
+}
+
+func (x *Partition) GetEofOffset() int64 {
+	if x != nil {
+		return x.EofOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetLoOffset() int64 {
+	if x != nil {
+		return x.LoOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetHiOffset() int64 {
+	if x != nil {
+		return x.HiOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetLsOffset() int64 {
+	if x != nil {
+		return x.LsOffset
+	}
+	return 0
+}
+
+func (x *Partition) GetConsumerLag() int64 {
+	if x != nil {
+		return x.ConsumerLag
+	}
+	return 0
+}
+
+func (x *Partition) GetConsumerLagStored() int64 {
+	if x != nil {
+		return x.ConsumerLagStored
+	}
+	return 0
+}
+
+func (x *Partition) GetLeaderEpoch() int64 {
+	if x != nil {
+		return x.LeaderEpoch
+	}
+	return 0
+}
+
+func (x *Partition) GetTxmsgs() int64 {
+	if x != nil {
+		return x.Txmsgs
+	}
+	return 0
+}
+
+func (x *Partition) GetTxbytes() int64 {
+	if x != nil {
+		return x.Txbytes
+	}
+	return 0
+}
+
+func (x *Partition) GetRxmsgs() int64 {
+	if x != nil {
+		return x.Rxmsgs
+	}
+	return 0
+}
+
+func (x *Partition) GetRxbytes() int64 {
+	if x != nil {
+		return x.Rxbytes
+	}
+	return 0
+}
+
+func (x *Partition) GetMsgs() int64 {
+	if x != nil {
+		return x.Msgs
+	}
+	return 0
+}
+
+func (x *Partition) GetRxVerDrops() int64 {
+	if x != nil {
+		return x.RxVerDrops
+	}
+	return 0
+}
+
+func (x *Partition) GetMsgsInflight() int64 {
+	if x != nil {
+		return x.MsgsInflight
+	}
+	return 0
+}
+
+func (x *Partition) GetNextAckSeq() int64 {
+	if x != nil {
+		return x.NextAckSeq
+	}
+	return 0
+}
+
+func (x *Partition) GetNextErrSeq() int64 {
+	if x != nil {
+		return x.NextErrSeq
+	}
+	return 0
+}
+
+func (x *Partition) GetAckedMsgid() int64 {
+	if x != nil {
+		return x.AckedMsgid
+	}
+	return 0
+}
+
+func (x *Partition) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+type ConsumerGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State           string             `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Stateage        int64              `protobuf:"varint,2,opt,name=stateage,proto3" json:"stateage,omitempty"`
+	JoinState       string             `protobuf:"bytes,3,opt,name=join_state,json=joinState,proto3" json:"join_state,omitempty"`
+	RebalanceAge    int64              `protobuf:"varint,4,opt,name=rebalance_age,json=rebalanceAge,proto3" json:"rebalance_age,omitempty"`
+	RebalanceCnt    int64              `protobuf:"varint,5,opt,name=rebalance_cnt,json=rebalanceCnt,proto3" json:"rebalance_cnt,omitempty"`
+	RebalanceReason string             `protobuf:"bytes,6,opt,name=rebalance_reason,json=rebalanceReason,proto3" json:"rebalance_reason,omitempty"`
+	AssignmentSize  int64              `protobuf:"varint,7,opt,name=assignment_size,json=assignmentSize,proto3" json:"assignment_size,omitempty"`
+	Extra           map[string]float64 `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *ConsumerGroup) Reset() {
+	*x = ConsumerGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConsumerGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumerGroup) ProtoMessage() {}
+
+func (x *ConsumerGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumerGroup.ProtoReflect.Descriptor instead.
+func (*ConsumerGroup) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConsumerGroup) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ConsumerGroup) GetStateage() int64 {
+	if x != nil {
+		return x.Stateage
+	}
+	return 0
+}
+
+func (x *ConsumerGroup) GetJoinState() string {
+	if x != nil {
+		return x.JoinState
+	}
+	return ""
+}
+
+func (x *ConsumerGroup) GetRebalanceAge() int64 {
+	if x != nil {
+		return x.RebalanceAge
+	}
+	return 0
+}
+
+func (x *ConsumerGroup) GetRebalanceCnt() int64 {
+	if x != nil {
+		return x.RebalanceCnt
+	}
+	return 0
+}
+
+func (x *ConsumerGroup) GetRebalanceReason() string {
+	if x != nil {
+		return x.RebalanceReason
+	}
+	return ""
+}
+
+func (x *ConsumerGroup) GetAssignmentSize() int64 {
+	if x != nil {
+		return x.AssignmentSize
+	}
+	return 0
+}
+
+func (x *ConsumerGroup) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+type EOS struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IdempState    string             `protobuf:"bytes,1,opt,name=idemp_state,json=idempState,proto3" json:"idemp_state,omitempty"`
+	IdempStateage int64              `protobuf:"varint,2,opt,name=idemp_stateage,json=idempStateage,proto3" json:"idemp_stateage,omitempty"`
+	TxnState      string             `protobuf:"bytes,3,opt,name=txn_state,json=txnState,proto3" json:"txn_state,omitempty"`
+	TxnStateage   int64              `protobuf:"varint,4,opt,name=txn_stateage,json=txnStateage,proto3" json:"txn_stateage,omitempty"`
+	TxnMayEnq     bool               `protobuf:"varint,5,opt,name=txn_may_enq,json=txnMayEnq,proto3" json:"txn_may_enq,omitempty"`
+	ProducerId    int64              `protobuf:"varint,6,opt,name=producer_id,json=producerId,proto3" json:"producer_id,omitempty"`
+	ProducerEpoch int64              `protobuf:"varint,7,opt,name=producer_epoch,json=producerEpoch,proto3" json:"producer_epoch,omitempty"`
+	EpochCnt      int64              `protobuf:"varint,8,opt,name=epoch_cnt,json=epochCnt,proto3" json:"epoch_cnt,omitempty"`
+	Extra         map[string]float64 `protobuf:"bytes,100,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *EOS) Reset() {
+	*x = EOS{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EOS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EOS) ProtoMessage() {}
+
+func (x *EOS) ProtoReflect() protoreflect.Message {
+	mi := &file_librdkafka_stats_v1_stats_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EOS.ProtoReflect.Descriptor instead.
+func (*EOS) Descriptor() ([]byte, []int) {
+	return file_librdkafka_stats_v1_stats_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EOS) GetIdempState() string {
+	if x != nil {
+		return x.IdempState
+	}
+	return ""
+}
+
+func (x *EOS) GetIdempStateage() int64 {
+	if x != nil {
+		return x.IdempStateage
+	}
+	return 0
+}
+
+func (x *EOS) GetTxnState() string {
+	if x != nil {
+		return x.TxnState
+	}
+	return ""
+}
+
+func (x *EOS) GetTxnStateage() int64 {
+	if x != nil {
+		return x.TxnStateage
+	}
+	return 0
+}
+
+func (x *EOS) GetTxnMayEnq() bool {
+	if x != nil {
+		return x.TxnMayEnq
+	}
+	return false
+}
+
+func (x *EOS) GetProducerId() int64 {
+	if x != nil {
+		return x.ProducerId
+	}
+	return 0
+}
+
+func (x *EOS) GetProducerEpoch() int64 {
+	if x != nil {
+		return x.ProducerEpoch
+	}
+	return 0
+}
+
+func (x *EOS) GetEpochCnt() int64 {
+	if x != nil {
+		return x.EpochCnt
+	}
+	return 0
+}
+
+func (x *EOS) GetExtra() map[string]float64 {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+var File_librdkafka_stats_v1_stats_proto protoreflect.FileDescriptor
+
+var file_librdkafka_stats_v1_stats_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2f, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x13, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x22, 0x62, 0x0a, 0x0b, 0x50, 0x75, 0x73, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b,
+	0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x04, 0x6a, 0x73, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x42,
+	0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x0e, 0x0a, 0x0c, 0x50, 0x75,
+	0x73, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x65, 0x0a, 0x13, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x22, 0xac, 0x08, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x03, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x71,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x71, 0x12, 0x17,
+	0x0a, 0x07, 0x6d, 0x73, 0x67, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x6d, 0x73, 0x67, 0x43, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x73, 0x67, 0x4d, 0x61, 0x78, 0x12, 0x20, 0x0a, 0x0c, 0x6d,
+	0x73, 0x67, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x6d, 0x73, 0x67, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x61, 0x78, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x43, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x63,
+	0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x43, 0x61, 0x63, 0x68, 0x65, 0x43, 0x6e, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x62, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6c, 0x69,
+	0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x3e, 0x0a,
+	0x06, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e,
+	0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x12, 0x36, 0x0a,
+	0x04, 0x63, 0x67, 0x72, 0x70, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6c, 0x69,
+	0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x04, 0x63, 0x67, 0x72, 0x70, 0x12, 0x2a, 0x0a, 0x03, 0x65, 0x6f, 0x73, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x4f, 0x53, 0x52, 0x03, 0x65, 0x6f,
+	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74,
+	0x78, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02,
+	0x72, 0x78, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x72, 0x78, 0x12, 0x19, 0x0a, 0x08,
+	0x72, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x72, 0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x78, 0x6d, 0x73, 0x67,
+	0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x74, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x78, 0x6d, 0x73, 0x67, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x78, 0x6d, 0x73, 0x67, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x18, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x72, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x78, 0x6d, 0x73,
+	0x67, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72,
+	0x78, 0x6d, 0x73, 0x67, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x05, 0x65, 0x78, 0x74,
+	0x72, 0x61, 0x18, 0x64, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64,
+	0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x1a, 0x57, 0x0a, 0x0c, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b,
+	0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x55, 0x0a, 0x0b, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x30, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x38, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xe2, 0x0a, 0x0a, 0x06, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x6e, 0x6f, 0x64, 0x65, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x43, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e,
+	0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x5f, 0x6d, 0x73, 0x67, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x4d, 0x73, 0x67, 0x43,
+	0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x61, 0x69, 0x74, 0x72, 0x65, 0x73, 0x70, 0x5f, 0x63,
+	0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x61, 0x69, 0x74, 0x72, 0x65,
+	0x73, 0x70, 0x43, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x61, 0x69, 0x74, 0x72, 0x65, 0x73,
+	0x70, 0x5f, 0x6d, 0x73, 0x67, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0e, 0x77, 0x61, 0x69, 0x74, 0x72, 0x65, 0x73, 0x70, 0x4d, 0x73, 0x67, 0x43, 0x6e, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x78, 0x12,
+	0x18, 0x0a, 0x07, 0x74, 0x78, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x74, 0x78, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x78, 0x65,
+	0x72, 0x72, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x74, 0x78, 0x65, 0x72, 0x72,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x78, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x78, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x74, 0x78, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x74, 0x78, 0x69, 0x64, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72,
+	0x65, 0x71, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x72, 0x78,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x72, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x78,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x78, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x78, 0x65, 0x72, 0x72, 0x73, 0x18, 0x13,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x72, 0x78, 0x65, 0x72, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x72, 0x78, 0x63, 0x6f, 0x72, 0x72, 0x69, 0x64, 0x65, 0x72, 0x72, 0x73, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x72, 0x78, 0x63, 0x6f, 0x72, 0x72, 0x69, 0x64, 0x65, 0x72, 0x72, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x78, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x78, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x78, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x72, 0x78, 0x69, 0x64, 0x6c, 0x65, 0x12, 0x36, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x17, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x2e, 0x52, 0x65, 0x71, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x1b,
+	0x0a, 0x09, 0x7a, 0x62, 0x75, 0x66, 0x5f, 0x67, 0x72, 0x6f, 0x77, 0x18, 0x18, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x7a, 0x62, 0x75, 0x66, 0x47, 0x72, 0x6f, 0x77, 0x12, 0x19, 0x0a, 0x08, 0x62,
+	0x75, 0x66, 0x5f, 0x67, 0x72, 0x6f, 0x77, 0x18, 0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62,
+	0x75, 0x66, 0x47, 0x72, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x61, 0x6b, 0x65, 0x75, 0x70,
+	0x73, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x77, 0x61, 0x6b, 0x65, 0x75, 0x70, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x73, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x73, 0x18, 0x1c, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x73, 0x12, 0x3c,
+	0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x1d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x52, 0x0a, 0x69, 0x6e, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x42, 0x0a, 0x0e,
+	0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b,
+	0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x52, 0x0d, 0x6f, 0x75, 0x74, 0x62, 0x75, 0x66, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x2d, 0x0a, 0x03, 0x72, 0x74, 0x74, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x03, 0x72, 0x74, 0x74, 0x12,
+	0x37, 0x0a, 0x08, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x18, 0x20, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x08,
+	0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x12, 0x42, 0x0a, 0x07, 0x74, 0x6f, 0x70, 0x70,
+	0x61, 0x72, 0x73, 0x18, 0x21, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x6c, 0x69, 0x62, 0x72,
+	0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x54, 0x6f, 0x70, 0x70, 0x61, 0x72, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x07, 0x74, 0x6f, 0x70, 0x70, 0x61, 0x72, 0x73, 0x12, 0x3c, 0x0a, 0x05,
+	0x65, 0x78, 0x74, 0x72, 0x61, 0x18, 0x64, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6c, 0x69,
+	0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x1a, 0x36, 0x0a, 0x08, 0x52, 0x65,
+	0x71, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x57, 0x0a, 0x0c, 0x54, 0x6f, 0x70, 0x70, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x70, 0x70, 0x61, 0x72,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x38, 0x0a, 0x0a, 0x45,
+	0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3c, 0x0a, 0x06, 0x54, 0x6f, 0x70, 0x70, 0x61, 0x72, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0xa5, 0x02, 0x0a, 0x06, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x10,
+	0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x69, 0x6e,
+	0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d,
+	0x61, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x76, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x03, 0x61, 0x76, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x64, 0x65, 0x76,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x74, 0x64, 0x64, 0x65, 0x76, 0x12, 0x10,
+	0x0a, 0x03, 0x70, 0x35, 0x30, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x35, 0x30,
+	0x12, 0x10, 0x0a, 0x03, 0x70, 0x37, 0x35, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70,
+	0x37, 0x35, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x30, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x03, 0x70, 0x39, 0x30, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x35, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x03, 0x70, 0x39, 0x35, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x39, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x39, 0x39, 0x12, 0x15, 0x0a, 0x06, 0x70, 0x39, 0x39, 0x5f,
+	0x39, 0x39, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x39, 0x39, 0x39, 0x39, 0x12,
+	0x1e, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x6f, 0x66, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x6f, 0x66, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x64, 0x72, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x68, 0x64, 0x72, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6e, 0x74,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x63, 0x6e, 0x74, 0x22, 0xe8, 0x03, 0x0a, 0x05,
+	0x54, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x41, 0x67, 0x65,
+	0x12, 0x39, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x63, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x08, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x63, 0x6e, 0x74, 0x12, 0x4a, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64,
+	0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x6f, 0x70, 0x69, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x3b, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18, 0x64, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x2e, 0x45, 0x78, 0x74, 0x72,
+	0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x1a, 0x5d, 0x0a,
+	0x0f, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x34, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x38, 0x0a, 0x0a,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x98, 0x0a, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x75,
+	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x71, 0x5f, 0x63,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x71, 0x43, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x73, 0x67, 0x71, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x73, 0x67, 0x71, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x78, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x67, 0x71, 0x5f, 0x63, 0x6e,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x78, 0x6d, 0x69, 0x74, 0x4d, 0x73, 0x67,
+	0x71, 0x43, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x78, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x67,
+	0x71, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x78,
+	0x6d, 0x69, 0x74, 0x4d, 0x73, 0x67, 0x71, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x71, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x66, 0x65, 0x74, 0x63, 0x68, 0x71, 0x43, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66,
+	0x65, 0x74, 0x63, 0x68, 0x71, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x66, 0x65, 0x74, 0x63, 0x68, 0x71, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x66, 0x65, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x71, 0x75, 0x65, 0x72, 0x79, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x70, 0x70, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x70, 0x70, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x5f,
+	0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x11, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74,
+	0x65, 0x64, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x6c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x14, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x4c, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6f, 0x66, 0x5f, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x6f, 0x66, 0x4f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x5f, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x6f, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x69, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x68, 0x69, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x73, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x6c, 0x73, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x67, 0x18, 0x18, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x4c, 0x61, 0x67, 0x12,
+	0x2e, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x67, 0x5f,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x63, 0x6f,
+	0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x4c, 0x61, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18,
+	0x1a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x70, 0x6f,
+	0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x74, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x78,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x78, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x1d,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x72, 0x78, 0x6d, 0x73, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x72, 0x78, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72,
+	0x78, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x1f,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6d, 0x73, 0x67, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x72, 0x78,
+	0x5f, 0x76, 0x65, 0x72, 0x5f, 0x64, 0x72, 0x6f, 0x70, 0x73, 0x18, 0x20, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x72, 0x78, 0x56, 0x65, 0x72, 0x44, 0x72, 0x6f, 0x70, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x6d, 0x73, 0x67, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x21, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x73, 0x67, 0x73, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x12, 0x20, 0x0a, 0x0c, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x65,
+	0x71, 0x18, 0x22, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x41, 0x63, 0x6b,
+	0x53, 0x65, 0x71, 0x12, 0x20, 0x0a, 0x0c, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x5f,
+	0x73, 0x65, 0x71, 0x18, 0x23, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x45,
+	0x72, 0x72, 0x53, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x6d,
+	0x73, 0x67, 0x69, 0x64, 0x18, 0x24, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x63, 0x6b, 0x65,
+	0x64, 0x4d, 0x73, 0x67, 0x69, 0x64, 0x12, 0x3f, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18,
+	0x64, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66,
+	0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x1a, 0x38, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x72, 0x61,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xfd, 0x02, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6a, 0x6f, 0x69, 0x6e, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6a, 0x6f, 0x69, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x72, 0x65, 0x62,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x62,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x72, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x6e, 0x74, 0x12, 0x29,
+	0x0a, 0x10, 0x72, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x62, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x73, 0x73,
+	0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x43, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18, 0x64, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2d, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x1a, 0x38, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x72, 0x61,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x87, 0x03, 0x0a, 0x03, 0x45, 0x4f, 0x53, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x64, 0x65,
+	0x6d, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x64,
+	0x65, 0x6d, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x53, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x78, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x78, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x74, 0x78, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x78, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x61, 0x67,
+	0x65, 0x12, 0x1e, 0x0a, 0x0b, 0x74, 0x78, 0x6e, 0x5f, 0x6d, 0x61, 0x79, 0x5f, 0x65, 0x6e, 0x71,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x78, 0x6e, 0x4d, 0x61, 0x79, 0x45, 0x6e,
+	0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x65,
+	0x70, 0x6f, 0x63, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x65, 0x72, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x70, 0x6f,
+	0x63, 0x68, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x70,
+	0x6f, 0x63, 0x68, 0x43, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18,
+	0x64, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66,
+	0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x4f, 0x53, 0x2e,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x78, 0x74, 0x72,
+	0x61, 0x1a, 0x38, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x72, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0xb8, 0x01, 0x0a, 0x0c,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x04,
+	0x50, 0x75, 0x73, 0x68, 0x12, 0x20, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b,
+	0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61,
+	0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x73,
+	0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x0b, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x20, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x64,
+	0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x75, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6c, 0x69, 0x62,
+	0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x42, 0x36, 0x5a, 0x34, 0x6d, 0x63, 0x6f, 0x6c, 0x6f, 0x6d,
+	0x65, 0x72, 0x63, 0x2f, 0x6c, 0x69, 0x62, 0x72, 0x64, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2d, 0x70,
+	0x72, 0x6f, 0x6d, 0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x2d, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_librdkafka_stats_v1_stats_proto_rawDescOnce sync.Once
+	file_librdkafka_stats_v1_stats_proto_rawDescData = file_librdkafka_stats_v1_stats_proto_rawDesc
+)
+
+func file_librdkafka_stats_v1_stats_proto_rawDescGZIP() []byte {
+	file_librdkafka_stats_v1_stats_proto_rawDescOnce.Do(func() {
+		file_librdkafka_stats_v1_stats_proto_rawDescData = protoimpl.X.CompressGZIP(file_librdkafka_stats_v1_stats_proto_rawDescData)
+	})
+	return file_librdkafka_stats_v1_stats_proto_rawDescData
+}
+
+var file_librdkafka_stats_v1_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_librdkafka_stats_v1_stats_proto_goTypes = []interface{}{
+	(*PushRequest)(nil),         // 0: librdkafka.stats.v1.PushRequest
+	(*PushResponse)(nil),        // 1: librdkafka.stats.v1.PushResponse
+	(*StreamStatsResponse)(nil), // 2: librdkafka.stats.v1.StreamStatsResponse
+	(*Stats)(nil),               // 3: librdkafka.stats.v1.Stats
+	(*Broker)(nil),              // 4: librdkafka.stats.v1.Broker
+	(*Toppar)(nil),              // 5: librdkafka.stats.v1.Toppar
+	(*Window)(nil),              // 6: librdkafka.stats.v1.Window
+	(*Topic)(nil),               // 7: librdkafka.stats.v1.Topic
+	(*Partition)(nil),           // 8: librdkafka.stats.v1.Partition
+	(*ConsumerGroup)(nil),       // 9: librdkafka.stats.v1.ConsumerGroup
+	(*EOS)(nil),                 // 10: librdkafka.stats.v1.EOS
+	nil,                         // 11: librdkafka.stats.v1.Stats.BrokersEntry
+	nil,                         // 12: librdkafka.stats.v1.Stats.TopicsEntry
+	nil,                         // 13: librdkafka.stats.v1.Stats.ExtraEntry
+	nil,                         // 14: librdkafka.stats.v1.Broker.ReqEntry
+	nil,                         // 15: librdkafka.stats.v1.Broker.TopparsEntry
+	nil,                         // 16: librdkafka.stats.v1.Broker.ExtraEntry
+	nil,                         // 17: librdkafka.stats.v1.Topic.PartitionsEntry
+	nil,                         // 18: librdkafka.stats.v1.Topic.ExtraEntry
+	nil,                         // 19: librdkafka.stats.v1.Partition.ExtraEntry
+	nil,                         // 20: librdkafka.stats.v1.ConsumerGroup.ExtraEntry
+	nil,                         // 21: librdkafka.stats.v1.EOS.ExtraEntry
+}
+var file_librdkafka_stats_v1_stats_proto_depIdxs = []int32{
+	3,  // 0: librdkafka.stats.v1.PushRequest.stats:type_name -> librdkafka.stats.v1.Stats
+	11, // 1: librdkafka.stats.v1.Stats.brokers:type_name -> librdkafka.stats.v1.Stats.BrokersEntry
+	12, // 2: librdkafka.stats.v1.Stats.topics:type_name -> librdkafka.stats.v1.Stats.TopicsEntry
+	9,  // 3: librdkafka.stats.v1.Stats.cgrp:type_name -> librdkafka.stats.v1.ConsumerGroup
+	10, // 4: librdkafka.stats.v1.Stats.eos:type_name -> librdkafka.stats.v1.EOS
+	13, // 5: librdkafka.stats.v1.Stats.extra:type_name -> librdkafka.stats.v1.Stats.ExtraEntry
+	14, // 6: librdkafka.stats.v1.Broker.req:type_name -> librdkafka.stats.v1.Broker.ReqEntry
+	6,  // 7: librdkafka.stats.v1.Broker.int_latency:type_name -> librdkafka.stats.v1.Window
+	6,  // 8: librdkafka.stats.v1.Broker.outbuf_latency:type_name -> librdkafka.stats.v1.Window
+	6,  // 9: librdkafka.stats.v1.Broker.rtt:type_name -> librdkafka.stats.v1.Window
+	6,  // 10: librdkafka.stats.v1.Broker.throttle:type_name -> librdkafka.stats.v1.Window
+	15, // 11: librdkafka.stats.v1.Broker.toppars:type_name -> librdkafka.stats.v1.Broker.TopparsEntry
+	16, // 12: librdkafka.stats.v1.Broker.extra:type_name -> librdkafka.stats.v1.Broker.ExtraEntry
+	6,  // 13: librdkafka.stats.v1.Topic.batchsize:type_name -> librdkafka.stats.v1.Window
+	6,  // 14: librdkafka.stats.v1.Topic.batchcnt:type_name -> librdkafka.stats.v1.Window
+	17, // 15: librdkafka.stats.v1.Topic.partitions:type_name -> librdkafka.stats.v1.Topic.PartitionsEntry
+	18, // 16: librdkafka.stats.v1.Topic.extra:type_name -> librdkafka.stats.v1.Topic.ExtraEntry
+	19, // 17: librdkafka.stats.v1.Partition.extra:type_name -> librdkafka.stats.v1.Partition.ExtraEntry
+	20, // 18: librdkafka.stats.v1.ConsumerGroup.extra:type_name -> librdkafka.stats.v1.ConsumerGroup.ExtraEntry
+	21, // 19: librdkafka.stats.v1.EOS.extra:type_name -> librdkafka.stats.v1.EOS.ExtraEntry
+	4,  // 20: librdkafka.stats.v1.Stats.BrokersEntry.value:type_name -> librdkafka.stats.v1.Broker
+	7,  // 21: librdkafka.stats.v1.Stats.TopicsEntry.value:type_name -> librdkafka.stats.v1.Topic
+	5,  // 22: librdkafka.stats.v1.Broker.TopparsEntry.value:type_name -> librdkafka.stats.v1.Toppar
+	8,  // 23: librdkafka.stats.v1.Topic.PartitionsEntry.value:type_name -> librdkafka.stats.v1.Partition
+	0,  // 24: librdkafka.stats.v1.StatsService.Push:input_type -> librdkafka.stats.v1.PushRequest
+	0,  // 25: librdkafka.stats.v1.StatsService.StreamStats:input_type -> librdkafka.stats.v1.PushRequest
+	1,  // 26: librdkafka.stats.v1.StatsService.Push:output_type -> librdkafka.stats.v1.PushResponse
+	2,  // 27: librdkafka.stats.v1.StatsService.StreamStats:output_type -> librdkafka.stats.v1.StreamStatsResponse
+	26, // [26:28] is the sub-list for method output_type
+	24, // [24:26] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_librdkafka_stats_v1_stats_proto_init() }
+func file_librdkafka_stats_v1_stats_proto_init() {
+	if File_librdkafka_stats_v1_stats_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_librdkafka_stats_v1_stats_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Stats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Broker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Toppar); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Window); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Topic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Partition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConsumerGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_librdkafka_stats_v1_stats_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EOS); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_librdkafka_stats_v1_stats_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*PushRequest_Stats)(nil),
+		(*PushRequest_Json)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_librdkafka_stats_v1_stats_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_librdkafka_stats_v1_stats_proto_goTypes,
+		DependencyIndexes: file_librdkafka_stats_v1_stats_proto_depIdxs,
+		MessageInfos:      file_librdkafka_stats_v1_stats_proto_msgTypes,
+	}.Build()
+	File_librdkafka_stats_v1_stats_proto = out.File
+	file_librdkafka_stats_v1_stats_proto_rawDesc = nil
+	file_librdkafka_stats_v1_stats_proto_goTypes = nil
+	file_librdkafka_stats_v1_stats_proto_depIdxs = nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	PUSHGATEWAY_PATH = config.PUSHGATEWAY_PATH
+	BASE64_SUFFIX    = "@base64"
+)
+
+// pushgatewayHandler serves the Pushgateway API on /metrics/job/<job>{/<label>/<value>}.
+// PUT replaces the clients of the group with the pushed one, POST replaces the pushed
+// client only and DELETE removes all the clients of the group, in the exporter of the tenant
+// named by the request.
+func pushgatewayHandler(w http.ResponseWriter, r *http.Request) {
+	group, err := parseGroup(strings.TrimPrefix(r.URL.EscapedPath(), PUSHGATEWAY_PATH))
+	if _, ok := group[TENANT_LABEL]; ok && err == nil && tenantExps.enabled() {
+		// The admin metrics label the series of every tenant with their tenant.
+		err = fmt.Errorf("grouping label %q is reserved for the tenants", TENANT_LABEL)
+	}
+	if err != nil {
+		log.Println(err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		handleGroupPush(w, r, group, r.Method == http.MethodPut)
+	case http.MethodDelete:
+		tenant, ok := tenantExps.tenant(w, r)
+		if !ok {
+			return
+		}
+		// Deleting the group of a tenant that never pushed stats doesn't create its exporter.
+		exp := promExp
+		if tenant != "" {
+			exp = tenantExps.lookup(tenant)
+		}
+		if exp != nil {
+			if err := exp.ValidateGroup(group); err != nil {
+				log.Println(err)
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			exp.DeleteGroup(group)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func handleGroupPush(w http.ResponseWriter, r *http.Request, group prom.Group, replace bool) {
+	defer r.Body.Close()
+	log.Println(">> Handling grouped stats from requester:: ", r.Header.Get("User-Agent"))
+	exp, ok := tenantExps.exporter(w, r)
+	if !ok {
+		return
+	}
+	var s *stats.Stats
+	if !readPayload(w, r, exp, func(body io.Reader) (err error) {
+		s, err = stats.Decode(body)
+		return err
+	}) {
+		return
+	}
+	if err := exp.UpdateGroupStats(group, s, clientInfo(r), replace); err != nil {
+		log.Println(err)
+		var groupErr *prom.GroupError
+		if errors.As(err, &groupErr) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		writeUpdateError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// parseGroup parses the grouping key of a job/<job>{/<label>/<value>} path. As with the
+// Pushgateway, a <label>@base64 name means its value is base64url encoded, so it can hold
+// slashes or be empty ("=").
+func parseGroup(path string) (prom.Group, error) {
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("grouping key %q: missing value of label %q", path, parts[len(parts)-1])
+	}
+	group := make(prom.Group)
+	for i := 0; i < len(parts); i += 2 {
+		name, value, err := groupLabel(parts[i], parts[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("grouping key %q: %w", path, err)
+		}
+		if i == 0 && name != "job" {
+			return nil, fmt.Errorf("grouping key %q: must start with the job", path)
+		}
+		if _, ok := group[name]; ok {
+			return nil, fmt.Errorf("grouping key %q: duplicated label %q", path, name)
+		}
+		group[name] = value
+	}
+	if group["job"] == "" {
+		return nil, fmt.Errorf("grouping key %q: empty job", path)
+	}
+	return group, nil
+}
+
+func groupLabel(name, value string) (string, string, error) {
+	name, encoded := strings.CutSuffix(name, BASE64_SUFFIX)
+	if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+		return "", "", fmt.Errorf("invalid label name %q", name)
+	}
+	value, err := url.PathUnescape(value)
+	if err != nil {
+		return "", "", fmt.Errorf("label %q: %w", name, err)
+	}
+	if encoded {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return "", "", fmt.Errorf("label %q: invalid base64 value: %w", name, err)
+		}
+		value = string(decoded)
+	}
+	return name, value, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+)
+
+// startDefault sets up the exporter with the default configuration and returns the HTTP API.
+func startDefault(t *testing.T) http.Handler {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "metrics_path: /metrics\n")
+	r := start(t, path)
+	conf, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newMux(conf, r)
+}
+
+// renamed returns the stats payload of another instance of the client, named name.
+func renamed(t *testing.T, payload []byte, name string) []byte {
+	t.Helper()
+	var s map[string]any
+	if err := json.Unmarshal(payload, &s); err != nil {
+		t.Fatal(err)
+	}
+	s["name"] = name
+	out, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// PUT replaces the clients of a group, POST only the pushed client and DELETE removes the group.
+func TestPushgatewayGroups(t *testing.T) {
+	mux := startDefault(t)
+	first := renamed(t, loadStats(t), "rdkafka#producer-1")
+	second := renamed(t, loadStats(t), "rdkafka#producer-2")
+	app := map[string]string{"job": "app"}
+	other := map[string]string{"job": "other"}
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		body        []byte
+		app, others int // clients of the groups once applied
+	}{
+		{"put", "PUT", "job/app", first, 1, 0},
+		{"post another client", "POST", "job/app", second, 2, 0},
+		{"post the same client", "POST", "job/app", first, 2, 0},
+		{"put replaces", "PUT", "job/app", second, 1, 0},
+		{"other group", "PUT", "job/other", first, 1, 1},
+		{"delete", "DELETE", "job/app", nil, 0, 1},
+	} {
+		want := http.StatusOK
+		if tc.method == "DELETE" {
+			want = http.StatusAccepted
+		}
+		if status := serve(mux, tc.method, PUSHGATEWAY_PATH+tc.path, tc.body); status != want {
+			t.Fatalf("%s: got status %d, want %d", tc.name, status, want)
+		}
+		if n := clients(t, promExp.Registry, app); n != tc.app {
+			t.Errorf("%s: got %d clients of job app, want %d", tc.name, n, tc.app)
+		}
+		if n := clients(t, promExp.Registry, other); n != tc.others {
+			t.Errorf("%s: got %d clients of job other, want %d", tc.name, n, tc.others)
+		}
+	}
+}
+
+// Grouping labels replacing the labels of the series would make the series of clients identical.
+func TestPushgatewayLabelCollision(t *testing.T) {
+	mux := startDefault(t)
+	payload := loadStats(t)
+	for _, tc := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{"PUT", "job/app/client_id/a", http.StatusBadRequest},
+		{"POST", "job/app/topic/t", http.StatusBadRequest},
+		{"PUT", "job/app/broker/b", http.StatusBadRequest},
+		{"PUT", "job/app/user_agent/x", http.StatusBadRequest},
+		{"DELETE", "job/app/partition/0", http.StatusBadRequest},
+		{"PUT", "job/app/instance/a", http.StatusOK},
+	} {
+		if status := serve(mux, tc.method, PUSHGATEWAY_PATH+tc.path, payload); status != tc.status {
+			t.Errorf("%s %s: got status %d, want %d", tc.method, tc.path, status, tc.status)
+		}
+	}
+	// Another client keeps the exporter gathering.
+	serve(mux, "PUT", PUSHGATEWAY_PATH+"job/app/instance/b", renamed(t, payload, "rdkafka#producer-2"))
+	if _, err := promExp.Registry.Gather(); err != nil {
+		t.Error(err)
+	}
+}
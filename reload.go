@@ -0,0 +1,142 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const RELOAD_PATH = config.RELOAD_PATH
+
+// reloader reloads the configuration and the mappings on SIGHUP and POST /-/reload,
+// without restarting the exporter, so the series and counters of the clients are kept.
+type reloader struct {
+	mutex      sync.Mutex
+	configFile string
+	started    *config.Config // configuration the exporter was started with
+	successful prometheus.Gauge
+	timestamp  prometheus.Gauge
+}
+
+func newReloader(configFile string, conf *config.Config) (*reloader, error) {
+	r := &reloader{
+		configFile: configFile,
+		started:    conf,
+		successful: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: promExp.Prefix + prom.EXPORTER + "config_last_reload_successful",
+			Help: "Whether the last configuration reload succeeded.",
+		}),
+		timestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: promExp.Prefix + prom.EXPORTER + "config_last_reload_success_timestamp_seconds",
+			Help: "Unix time of the last successful configuration reload.",
+		}),
+	}
+	r.successful.Set(1)
+	r.timestamp.SetToCurrentTime()
+	for _, c := range []prometheus.Collector{r.successful, r.timestamp} {
+		if err := promExp.Registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// watchSignals reloads the configuration on every SIGHUP.
+func (r *reloader) watchSignals() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		r.reload()
+	}
+}
+
+func (r *reloader) handler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.reload(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// reload applies the configuration and the mappings, keeping the running ones on failure.
+func (r *reloader) reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	log.Println("Reloading the configuration")
+	if err := r.apply(); err != nil {
+		log.Println("Reload failed: ", err)
+		r.successful.Set(0)
+		return err
+	}
+	r.successful.Set(1)
+	r.timestamp.SetToCurrentTime()
+	return nil
+}
+
+func (r *reloader) apply() error {
+	conf, err := config.Load(r.configFile)
+	if err != nil {
+		return err
+	}
+	mappings, err := loadMappings(conf)
+	if err != nil {
+		return err
+	}
+	settings, err := settings(conf)
+	if err != nil {
+		return err
+	}
+	// Everything is built before anything is applied, so a failed reload changes nothing.
+	reload, err := promExp.PrepareReload(mappings, settings)
+	if err != nil {
+		return err
+	}
+	if err := tenantExps.reload(conf, mappings, settings, reload); err != nil {
+		return err
+	}
+	maxPayloadSize.Store(conf.Limits.MaxPayloadSize)
+	if changed := restartRequired(r.started, conf); len(changed) > 0 {
+		log.Printf("Changes to %s are only applied on restart", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// restartRequired lists the settings changed since the exporter was started that can't be reloaded.
+func restartRequired(started, conf *config.Config) []string {
+	var changed []string
+	for _, setting := range []struct {
+		name        string
+		started, to interface{}
+	}{
+		{"listen", started.ListenAddr(), conf.ListenAddr()},
+		{"metrics_path", started.MetricsPath, conf.MetricsPath},
+		{"ingest_path", started.IngestPath, conf.IngestPath},
+		{"metrics.prefix", started.Metrics.Prefix, conf.Metrics.Prefix},
+		{"metrics.labels", started.Metrics.Labels, conf.Metrics.Labels},
+		{"ingest", started.Ingest, conf.Ingest},
+		{"otlp", started.OTLP, conf.OTLP},
+		{"remote_write", started.RemoteWrite, conf.RemoteWrite},
+		{"tenants.enabled", started.Tenants.Enabled, conf.Tenants.Enabled},
+	} {
+		if !reflect.DeepEqual(setting.started, setting.to) {
+			changed = append(changed, setting.name)
+		}
+	}
+	return changed
+}
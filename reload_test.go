@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// descCollector describes a metric it never collects.
+type descCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c *descCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+func (c *descCollector) Collect(chan<- prometheus.Metric)    {}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// start sets up the exporters as main does, from a configuration file.
+func start(t *testing.T, path string) *reloader {
+	t.Helper()
+	conf, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promExp, err = newExporter(conf); err != nil {
+		t.Fatal(err)
+	}
+	maxPayloadSize.Store(conf.Limits.MaxPayloadSize)
+	tenantExps = newTenants(conf, promExp.Mappings, promExp.Settings)
+	r, err := newReloader(path, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// A reload failing for a tenant leaves every exporter and setting as it was.
+func TestReloadAllOrNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+metrics:
+  groups: [brokers]
+limits:
+  max_payload_size: 1024
+tenants:
+  enabled: true
+`)
+	r := start(t, path)
+	tenant, err := tenantExps.get("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics, tenantMetrics := len(promExp.Metrics), len(tenant.Metrics)
+
+	writeConfig(t, path, `
+limits:
+  max_payload_size: 2048
+tenants:
+  enabled: true
+  limits:
+    acme:
+      max_series: 10
+`)
+	// A collector of the tenant registers a metric of the reloaded mappings.
+	full, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(prom.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflict := &descCollector{full.Metrics["librdkafka_topics_age"].Desc}
+	tenant.Registry.MustRegister(conflict)
+	if err := r.reload(); err == nil {
+		t.Fatal("expected the reload of the tenant to fail")
+	}
+	if len(promExp.Metrics) != metrics || len(tenant.Metrics) != tenantMetrics {
+		t.Errorf("got %d and %d metrics after a failed reload, want %d and %d",
+			len(promExp.Metrics), len(tenant.Metrics), metrics, tenantMetrics)
+	}
+	if size := maxPayloadSize.Load(); size != 1024 {
+		t.Errorf("max payload size: got %d, want 1024", size)
+	}
+	if limit := tenant.Settings.Limits.Series; limit != 0 {
+		t.Errorf("tenant series limit: got %d, want 0", limit)
+	}
+
+	tenant.Registry.Unregister(conflict)
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(promExp.Metrics) <= metrics || len(tenant.Metrics) <= tenantMetrics {
+		t.Errorf("got %d and %d metrics after the reload, want more than %d and %d",
+			len(promExp.Metrics), len(tenant.Metrics), metrics, tenantMetrics)
+	}
+	if size := maxPayloadSize.Load(); size != 2048 {
+		t.Errorf("max payload size: got %d, want 2048", size)
+	}
+	if limit := tenant.Settings.Limits.Series; limit != 10 {
+		t.Errorf("tenant series limit: got %d, want 10", limit)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/remotewrite"
+)
+
+// startRemoteWrite sends the metrics to the remote_write URL, if set.
+func startRemoteWrite(conf config.RemoteWrite) {
+	url := conf.URL
+	if url == "" {
+		return
+	}
+	sender, err := remotewrite.NewSender(url, promExp.Prefix, promExp.Registry, promExp.Registerer)
+	if err != nil {
+		log.Fatal("Remote write: ", err)
+	}
+	sender.ExternalLabels = conf.ExternalLabels
+	sender.Headers = conf.Headers
+	sender.Interval = time.Duration(conf.IntervalMs) * time.Millisecond
+	sender.QueueCapacity = conf.QueueCapacity
+	log.Printf("Sending metrics to %s every %v", url, sender.Interval)
+	go sender.Run(context.Background())
+}
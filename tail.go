@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/ingest"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+)
+
+// startTail follows the configured files, "-" standing for stdin, feeding the stats logged in them to the exporter.
+// Log lines have no headers to name a tenant, so their stats go to the default exporter.
+func startTail(conf config.Tail) {
+	parser := &ingest.LineParser{}
+	if prefix := conf.Prefix; prefix != "" {
+		re, err := regexp.Compile(prefix)
+		if err != nil {
+			log.Fatal("Tail prefix: ", err)
+		}
+		parser.Prefix = re
+	}
+	handle := func(source string) func([]byte) {
+		return func(line []byte) {
+			s, err := parser.Parse(line)
+			if err != nil {
+				log.Printf("%s: %v", source, err)
+				return
+			}
+			if s == nil {
+				return
+			}
+			promExp.CountIngest(ingest.IDENTITY, int64(len(line)), int64(len(line)))
+			if err := promExp.UpdateClientStats(s, prom.ClientInfo{}); err != nil {
+				log.Printf("%s: %v", source, err)
+			}
+		}
+	}
+	for _, path := range conf.Files {
+		path = strings.TrimSpace(path)
+		log.Printf("Tailing stats from %s", path)
+		go func() {
+			var err error
+			if path == "-" {
+				err = ingest.ReadLines(os.Stdin, maxPayloadSize.Load(), handle("stdin"))
+			} else {
+				err = ingest.TailFile(context.Background(), path, false, maxPayloadSize.Load(), handle(path))
+			}
+			if err != nil {
+				log.Printf("Tail %s: %v", path, err)
+			}
+		}()
+	}
+}
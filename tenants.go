@@ -0,0 +1,338 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	TENANTS_PATH = config.TENANTS_PATH
+	TENANT_LABEL = "tenant"
+)
+
+var errTooManyTenants = errors.New("too many tenants")
+
+var tenantExps *tenants
+
+// tenants holds an exporter per tenant, with its own Registry, built on the first stats
+// pushed by the tenant with the current mappings and settings.
+type tenants struct {
+	mutex     sync.Mutex
+	on        bool         // tenants enabled at startup
+	options   prom.Options // prefix and labels of the exporters, set at startup
+	conf      *config.Config
+	mappings  *prom.Mappings
+	settings  prom.Settings
+	exporters map[string]*prom.PrometheusLibrdKafkaExporter
+}
+
+func newTenants(conf *config.Config, mappings *prom.Mappings, settings prom.Settings) *tenants {
+	return &tenants{
+		on:        conf.Tenants.Enabled,
+		options:   prom.Options{Prefix: conf.Metrics.Prefix, ConstLabels: prometheus.Labels(conf.Metrics.Labels)},
+		conf:      conf,
+		mappings:  mappings,
+		settings:  settings,
+		exporters: make(map[string]*prom.PrometheusLibrdKafkaExporter),
+	}
+}
+
+func (t *tenants) enabled() bool {
+	return t.on
+}
+
+// reload applies the reloaded configuration and mappings to the exporters of the tenants along
+// with the prepared reload of the default exporter: all of them are reloaded, or none.
+func (t *tenants) reload(conf *config.Config, mappings *prom.Mappings, settings prom.Settings, main *prom.PreparedReload) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	prevConf, prevMappings, prevSettings := t.conf, t.mappings, t.settings
+	t.conf, t.mappings, t.settings = conf, mappings, settings
+	names := append([]string{""}, t.names()...)
+	reloads := []*prom.PreparedReload{main}
+	for _, tenant := range names[1:] {
+		reload, err := t.exporters[tenant].PrepareReload(mappings, t.tenantSettings(tenant))
+		if err != nil {
+			t.conf, t.mappings, t.settings = prevConf, prevMappings, prevSettings
+			return fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		reloads = append(reloads, reload)
+	}
+	for i, reload := range reloads {
+		if err := reload.Commit(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				reloads[j].Rollback()
+			}
+			t.conf, t.mappings, t.settings = prevConf, prevMappings, prevSettings
+			if names[i] != "" {
+				err = fmt.Errorf("tenant %s: %w", names[i], err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// tenantSettings returns the settings of the exporter of a tenant, with its series limits. mutex must be held.
+func (t *tenants) tenantSettings(tenant string) prom.Settings {
+	settings := t.settings
+	limits := t.conf.SeriesLimits(tenant)
+	settings.Limits = prom.Limits{
+		SeriesPerClient:    limits.MaxSeriesPerClient,
+		TopicsPerClient:    limits.MaxTopicsPerClient,
+		PartitionsPerTopic: limits.MaxPartitionsPerTopic,
+		Series:             limits.MaxSeries,
+	}
+	return settings
+}
+
+// names returns the tenants, sorted. mutex must be held.
+func (t *tenants) names() []string {
+	names := make([]string, 0, len(t.exporters))
+	for tenant := range t.exporters {
+		names = append(names, tenant)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// get returns the exporter of a tenant, building it on first use.
+func (t *tenants) get(tenant string) (*prom.PrometheusLibrdKafkaExporter, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if exp, ok := t.exporters[tenant]; ok {
+		return exp, nil
+	}
+	if max := t.conf.Tenants.MaxTenants; max > 0 && len(t.exporters) >= max {
+		return nil, errTooManyTenants
+	}
+	opts := t.options
+	opts.Mappings = t.mappings
+	exp, err := prom.NewPrometheusLibrdKafkaExporterWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	exp.Settings = t.tenantSettings(tenant)
+	t.exporters[tenant] = exp
+	log.Printf("Tenant %s created", tenant)
+	return exp, nil
+}
+
+// lookup returns the exporter of a tenant, nil when the tenant never pushed stats.
+func (t *tenants) lookup(tenant string) *prom.PrometheusLibrdKafkaExporter {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.exporters[tenant]
+}
+
+// tenantError is a failure to resolve the tenant of a push, with the HTTP status it maps to.
+type tenantError struct {
+	status int
+	msg    string
+}
+
+func (e *tenantError) Error() string {
+	return e.msg
+}
+
+// resolve returns the tenant of a push, named by path or the tenants.header header, or owning the
+// bearer token of the Authorization header; empty when the push names none or tenants are disabled.
+// When tokens are configured, every push must carry a known token, the token of its tenant. header returns the value
+// of a header of the push: an HTTP header, gRPC metadata or a Kafka record header.
+func (t *tenants) resolve(path string, header func(string) string) (string, error) {
+	if !t.on {
+		return "", nil
+	}
+	t.mutex.Lock()
+	conf := t.conf.Tenants
+	t.mutex.Unlock()
+
+	tenant := path
+	if tenant == "" {
+		tenant = header(conf.Header)
+	}
+	owner, known := "", false
+	if token, ok := bearerToken(header("Authorization")); ok {
+		owner, known = conf.Tokens[token]
+	}
+	if tenant == "" && known {
+		tenant = owner
+	}
+	switch {
+	case len(conf.Tokens) > 0 && !known:
+		// With tokens, pushes to the default exporter are authenticated as well.
+		return "", &tenantError{http.StatusUnauthorized, "missing or unknown tenant token"}
+	case tenant == "":
+		return "", nil
+	case !config.ValidTenant(tenant):
+		return "", &tenantError{http.StatusBadRequest, fmt.Sprintf("invalid tenant %q", tenant)}
+	case len(conf.Tokens) > 0 && owner != tenant:
+		return "", &tenantError{http.StatusForbidden, fmt.Sprintf("token not allowed for tenant %q", tenant)}
+	}
+	return tenant, nil
+}
+
+// exporterOf returns the exporter of a tenant, building it on first use, promExp when tenant is empty.
+func (t *tenants) exporterOf(tenant string) (*prom.PrometheusLibrdKafkaExporter, error) {
+	if tenant == "" {
+		return promExp, nil
+	}
+	exp, err := t.get(tenant)
+	switch {
+	case errors.Is(err, errTooManyTenants):
+		return nil, &tenantError{http.StatusTooManyRequests, fmt.Sprintf("tenant %s: %v", tenant, err)}
+	case err != nil:
+		log.Printf("Tenant %s: %v", tenant, err)
+		return nil, &tenantError{http.StatusInternalServerError, "ERROR"}
+	}
+	return exp, nil
+}
+
+// tenant returns the tenant of a request, see resolve. On failure the error status is written and false returned.
+func (t *tenants) tenant(w http.ResponseWriter, r *http.Request) (string, bool) {
+	tenant, err := t.resolve(r.PathValue("tenant"), r.Header.Get)
+	if err != nil {
+		writeTenantError(w, err)
+		return "", false
+	}
+	return tenant, true
+}
+
+// exporter returns the exporter the stats of a request are pushed to, promExp when the request
+// names no tenant. On failure the error status is written and false returned.
+func (t *tenants) exporter(w http.ResponseWriter, r *http.Request) (*prom.PrometheusLibrdKafkaExporter, bool) {
+	tenant, ok := t.tenant(w, r)
+	if !ok {
+		return nil, false
+	}
+	exp, err := t.exporterOf(tenant)
+	if err != nil {
+		writeTenantError(w, err)
+		return nil, false
+	}
+	return exp, true
+}
+
+// statsHandler serves POST /tenants/{tenant}/stats, the ingest path of a tenant.
+func (t *tenants) statsHandler(w http.ResponseWriter, r *http.Request) {
+	if exp, ok := t.exporter(w, r); ok {
+		handlePost(w, r, exp)
+	}
+}
+
+// metricsHandler serves GET /tenants/{tenant}/metrics, the metrics of a tenant.
+func (t *tenants) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := t.tenant(w, r)
+	if !ok {
+		return
+	}
+	exp := t.lookup(tenant)
+	if exp == nil {
+		writeTenantError(w, &tenantError{http.StatusNotFound, fmt.Sprintf("unknown tenant %q", tenant)})
+		return
+	}
+	promhttp.HandlerFor(exp.Registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// adminHandler serves GET /tenants/metrics, the metrics of the default exporter and of all the tenants, with a tenant label.
+func (t *tenants) adminHandler(w http.ResponseWriter, r *http.Request) {
+	t.mutex.Lock()
+	adminToken := t.conf.Tenants.AdminToken
+	t.mutex.Unlock()
+	if adminToken != "" {
+		token, _ := bearerToken(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			writeTenantError(w, &tenantError{http.StatusUnauthorized, "missing or invalid admin token"})
+			return
+		}
+	}
+	promhttp.HandlerFor(t, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// Gather implements prometheus.Gatherer, merging the metrics of the default exporter and of all
+// the tenants, labeled with their tenant.
+func (t *tenants) Gather() ([]*dto.MetricFamily, error) {
+	t.mutex.Lock()
+	names := append([]string{""}, t.names()...)
+	exporters := []*prom.PrometheusLibrdKafkaExporter{promExp}
+	for _, tenant := range names[1:] {
+		exporters = append(exporters, t.exporters[tenant])
+	}
+	t.mutex.Unlock()
+
+	families := make(map[string]*dto.MetricFamily)
+	var errs []error
+	for i, exp := range exporters {
+		mfs, err := exp.Registry.Gather()
+		if err != nil && names[i] == "" {
+			errs = append(errs, err)
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", names[i], err))
+		}
+		for _, mf := range mfs {
+			if names[i] != "" {
+				for _, m := range mf.Metric {
+					m.Label = withTenantLabel(m.Label, names[i])
+				}
+			}
+			if family, ok := families[mf.GetName()]; ok {
+				family.Metric = append(family.Metric, mf.Metric...)
+			} else {
+				families[mf.GetName()] = mf
+			}
+		}
+	}
+	result := make([]*dto.MetricFamily, 0, len(families))
+	for _, mf := range families {
+		result = append(result, mf)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GetName() < result[j].GetName() })
+	return result, errors.Join(errs...)
+}
+
+// withTenantLabel sets the tenant label of a metric, replacing a label with the same name.
+func withTenantLabel(labels []*dto.LabelPair, tenant string) []*dto.LabelPair {
+	name := TENANT_LABEL
+	result := make([]*dto.LabelPair, 0, len(labels)+1)
+	for _, l := range labels {
+		if l.GetName() != name {
+			result = append(result, l)
+		}
+	}
+	result = append(result, &dto.LabelPair{Name: &name, Value: &tenant})
+	sort.Slice(result, func(i, j int) bool { return result[i].GetName() < result[j].GetName() })
+	return result
+}
+
+// bearerToken returns the token of an Authorization header.
+func bearerToken(authorization string) (string, bool) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	return token, ok && token != ""
+}
+
+func writeTenantError(w http.ResponseWriter, err error) {
+	status, msg := http.StatusInternalServerError, err.Error()
+	var tenantErr *tenantError
+	if errors.As(err, &tenantErr) {
+		status = tenantErr.status
+	}
+	log.Println(msg)
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(msg))
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/config"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/prom"
+	"mcolomerc/librdkafka-prometheus-exporter/pkg/stats"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startTenants sets up the exporters with the tenants a, b and c, each with its token, and returns the HTTP API.
+func startTenants(t *testing.T) http.Handler {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+tenants:
+  enabled: true
+  tokens:
+    token-a: a
+    token-b: b
+    token-c: c
+  admin_token: admin
+`)
+	r := start(t, path)
+	conf, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newMux(conf, r)
+}
+
+func loadStats(t *testing.T) []byte {
+	t.Helper()
+	payload, err := os.ReadFile("cmd/stats.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// serve sends a request to the HTTP API, headers being name, value pairs, and returns the response status.
+func serve(mux http.Handler, method, path string, body []byte, headers ...string) int {
+	return serveRecorder(mux, method, path, body, headers...).Code
+}
+
+// serveRecorder sends a request to the HTTP API, see serve, and returns the response.
+func serveRecorder(mux http.Handler, method, path string, body []byte, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	for i := 0; i < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// clients returns the number of clients gathered from g with the given labels.
+func clients(t *testing.T, g prometheus.Gatherer, labels map[string]string) int {
+	t.Helper()
+	mfs, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, mf := range mfs {
+		if mf.GetName() != "librdkafka_exporter_client_restarts_total" {
+			continue
+		}
+	metrics:
+		for _, m := range mf.Metric {
+			for name, value := range labels {
+				found := false
+				for _, l := range m.Label {
+					if l.GetName() == name && l.GetValue() == value {
+						found = true
+					}
+				}
+				if !found {
+					continue metrics
+				}
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// registry returns the Registry of a tenant, an empty one when the tenant has no exporter.
+func registry(tenant string) prometheus.Gatherer {
+	if exp := tenantExps.lookup(tenant); exp != nil {
+		return exp.Registry
+	}
+	return prometheus.NewRegistry()
+}
+
+// Every HTTP path pushes to the exporter of the tenant named by the request, and only to it.
+func TestTenantIsolation(t *testing.T) {
+	mux := startTenants(t)
+	payload := loadStats(t)
+	batch := append(append([]byte("["), payload...), ']')
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		path    string
+		body    []byte
+		headers []string
+		status  int
+	}{
+		{"tenant path", "POST", "/tenants/a/stats", payload, []string{"Authorization", "Bearer token-a"}, http.StatusOK},
+		{"tenant header", "POST", "/", payload, []string{"X-Tenant", "b", "Authorization", "Bearer token-b"}, http.StatusOK},
+		{"tenant token", "POST", config.BATCH_PATH, batch, []string{"Authorization", "Bearer token-c"}, http.StatusOK},
+		{"pushgateway", "PUT", PUSHGATEWAY_PATH + "job/app", payload, []string{"X-Tenant", "a", "Authorization", "Bearer token-a"}, http.StatusOK},
+		{"default", "POST", "/", payload, nil, http.StatusUnauthorized},
+		{"default unknown token", "POST", "/", payload, []string{"Authorization", "Bearer token-d"}, http.StatusUnauthorized},
+		{"pushgateway default", "PUT", PUSHGATEWAY_PATH + "job/app", payload, nil, http.StatusUnauthorized},
+		{"missing token", "POST", config.BATCH_PATH, batch, []string{"X-Tenant", "a"}, http.StatusUnauthorized},
+		{"other token", "POST", "/", payload, []string{"X-Tenant", "a", "Authorization", "Bearer token-b"}, http.StatusForbidden},
+		{"pushgateway other token", "PUT", PUSHGATEWAY_PATH + "job/app", payload, []string{"X-Tenant", "b", "Authorization", "Bearer token-a"}, http.StatusForbidden},
+		{"pushgateway delete other token", "DELETE", PUSHGATEWAY_PATH + "job/app", nil, []string{"X-Tenant", "a", "Authorization", "Bearer token-b"}, http.StatusForbidden},
+		{"invalid tenant", "POST", "/", payload, []string{"X-Tenant", "a/b", "Authorization", "Bearer token-a"}, http.StatusBadRequest},
+		{"pushgateway tenant label", "PUT", PUSHGATEWAY_PATH + "job/app/tenant/b", payload, []string{"Authorization", "Bearer token-a"}, http.StatusBadRequest},
+	} {
+		if status := serve(mux, tc.method, tc.path, tc.body, tc.headers...); status != tc.status {
+			t.Errorf("%s: got status %d, want %d", tc.name, status, tc.status)
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		g       prometheus.Gatherer
+		clients int
+		groups  int
+	}{
+		{"default", promExp.Registry, 0, 0},
+		{"a", registry("a"), 2, 1},
+		{"b", registry("b"), 1, 0},
+		{"c", registry("c"), 1, 0},
+	} {
+		if n := clients(t, tc.g, nil); n != tc.clients {
+			t.Errorf("%s: got %d clients, want %d", tc.name, n, tc.clients)
+		}
+		if n := clients(t, tc.g, map[string]string{"job": "app"}); n != tc.groups {
+			t.Errorf("%s: got %d clients of the job, want %d", tc.name, n, tc.groups)
+		}
+	}
+
+	// Deleting the group of a tenant leaves the other exporters alone.
+	if status := serve(mux, "DELETE", PUSHGATEWAY_PATH+"job/app", nil, "Authorization", "Bearer token-b"); status != http.StatusAccepted {
+		t.Errorf("delete: got status %d, want %d", status, http.StatusAccepted)
+	}
+	if n := clients(t, registry("a"), map[string]string{"job": "app"}); n != 1 {
+		t.Errorf("a: got %d clients of the job after deleting the group of b, want 1", n)
+	}
+	if status := serve(mux, "DELETE", PUSHGATEWAY_PATH+"job/app", nil, "Authorization", "Bearer token-a"); status != http.StatusAccepted {
+		t.Errorf("delete: got status %d, want %d", status, http.StatusAccepted)
+	}
+	if n := clients(t, registry("a"), map[string]string{"job": "app"}); n != 0 {
+		t.Errorf("a: got %d clients of the job after deleting it, want 0", n)
+	}
+}
+
+// The admin metrics hold the default exporter, without a tenant label, and every tenant.
+func TestTenantAdminMetrics(t *testing.T) {
+	mux := startTenants(t)
+	payload := loadStats(t)
+	// Datagrams and tailed logs push to the default exporter.
+	s, err := stats.Decode(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := promExp.UpdateStats(s); err != nil {
+		t.Fatal(err)
+	}
+	serve(mux, "POST", "/tenants/a/stats", payload, "Authorization", "Bearer token-a")
+
+	if status := serve(mux, "GET", TENANTS_PATH+"metrics", nil); status != http.StatusUnauthorized {
+		t.Errorf("without the admin token: got status %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := serve(mux, "GET", TENANTS_PATH+"metrics", nil, "Authorization", "Bearer admin"); status != http.StatusOK {
+		t.Errorf("got status %d, want %d", status, http.StatusOK)
+	}
+	if n := clients(t, tenantExps, nil); n != 2 {
+		t.Errorf("got %d clients, want 2", n)
+	}
+	if n := clients(t, tenantExps, map[string]string{"tenant": "a"}); n != 1 {
+		t.Errorf("got %d clients of tenant a, want 1", n)
+	}
+}
+
+// gRPC calls and Kafka records name their tenant with metadata and headers.
+func TestTenantTransports(t *testing.T) {
+	startTenants(t)
+
+	for _, tc := range []struct {
+		name string
+		md   metadata.MD
+		exp  func() *prom.PrometheusLibrdKafkaExporter
+		code codes.Code
+	}{
+		{"default", nil, nil, codes.Unauthenticated},
+		{"tenant", metadata.Pairs("x-tenant", "a", "authorization", "Bearer token-a"), func() *prom.PrometheusLibrdKafkaExporter { return tenantExps.lookup("a") }, codes.OK},
+		{"token", metadata.Pairs("authorization", "Bearer token-b"), func() *prom.PrometheusLibrdKafkaExporter { return tenantExps.lookup("b") }, codes.OK},
+		{"missing token", metadata.Pairs("x-tenant", "a"), nil, codes.Unauthenticated},
+		{"other token", metadata.Pairs("x-tenant", "a", "authorization", "Bearer token-b"), nil, codes.PermissionDenied},
+		{"invalid tenant", metadata.Pairs("x-tenant", "a b", "authorization", "Bearer token-a"), nil, codes.InvalidArgument},
+	} {
+		exp, err := grpcExporter(metadata.NewIncomingContext(context.Background(), tc.md))
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.code)
+			continue
+		}
+		if tc.exp != nil && exp != tc.exp() {
+			t.Errorf("%s: wrong exporter", tc.name)
+		}
+	}
+
+	payload := loadStats(t)
+	handleRecord(&kgo.Record{Value: payload, Headers: []kgo.RecordHeader{
+		{Key: "x-tenant", Value: []byte("c")},
+		{Key: "authorization", Value: []byte("Bearer token-a")},
+	}})
+	if exp := tenantExps.lookup("c"); exp != nil {
+		t.Error("record pushed with the token of another tenant")
+	}
+	handleRecord(&kgo.Record{Value: payload, Headers: []kgo.RecordHeader{
+		{Key: "X-Tenant", Value: []byte("c")},
+		{Key: "Authorization", Value: []byte("Bearer token-c")},
+	}})
+	if n := clients(t, registry("c"), nil); n != 1 {
+		t.Errorf("c: got %d clients, want 1", n)
+	}
+	handleRecord(&kgo.Record{Value: payload})
+	if n := clients(t, promExp.Registry, nil); n != 0 {
+		t.Errorf("default: got %d clients, want 0", n)
+	}
+}